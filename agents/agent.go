@@ -0,0 +1,48 @@
+// Package agents pairs a system prompt with the subset of tools it is
+// allowed to call, so a "reviewer" agent can be scoped away from
+// bash/fileedit while a "coder" agent keeps the full toolbox. Mirrors the
+// agent-scoped-toolbox pattern from lmcli.
+package agents
+
+// Agent is a named persona: its own system prompt, the tools it may call,
+// and any files that should be preloaded into its context.
+type Agent struct {
+	Name         string
+	SystemPrompt string
+	AllowedTools []string // nil or empty means every registered tool is allowed
+	PreloadFiles []string
+	ToolPolicies map[string]ToolPolicy // per-tool override; unset tools fall back to DefaultToolPolicy
+
+	// Provider selects which ChatCompletionProvider backend runs this
+	// agent ("openrouter", "ollama", "anthropic", "openai", "gemini"); empty means
+	// the app's default (openrouter). Model is the backend-specific model
+	// name (e.g. "z-ai/glm-4.5" for openrouter, "qwen2.5-coder:7b" for
+	// ollama); empty means the app's default model for that backend.
+	Provider string
+	Model    string
+}
+
+// NewAgent creates an Agent with the given name, system prompt, and tool
+// scope.
+func NewAgent(name, systemPrompt string, allowedTools, preloadFiles []string) Agent {
+	return Agent{
+		Name:         name,
+		SystemPrompt: systemPrompt,
+		AllowedTools: allowedTools,
+		PreloadFiles: preloadFiles,
+	}
+}
+
+// AllowsTool reports whether the agent may call the named tool. An agent
+// with no AllowedTools set allows every tool.
+func (a Agent) AllowsTool(name string) bool {
+	if len(a.AllowedTools) == 0 {
+		return true
+	}
+	for _, t := range a.AllowedTools {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
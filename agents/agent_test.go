@@ -0,0 +1,42 @@
+package agents
+
+import "testing"
+
+func TestAgentAllowsTool(t *testing.T) {
+	full := NewAgent("coder", "prompt", nil, nil)
+	if !full.AllowsTool("bash") {
+		t.Error("Expected agent with no AllowedTools to allow every tool")
+	}
+
+	scoped := NewAgent("reviewer", "prompt", []string{"grep", "readfile"}, nil)
+	if !scoped.AllowsTool("grep") {
+		t.Error("Expected reviewer to allow grep")
+	}
+	if scoped.AllowsTool("bash") {
+		t.Error("Expected reviewer to disallow bash")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	coder, ok := registry.Get("coder")
+	if !ok {
+		t.Fatal("Expected built-in 'coder' agent to be registered")
+	}
+	if !coder.AllowsTool("bash") {
+		t.Error("Expected coder to allow bash")
+	}
+
+	reviewer, ok := registry.Get("reviewer")
+	if !ok {
+		t.Fatal("Expected built-in 'reviewer' agent to be registered")
+	}
+	if reviewer.AllowsTool("bash") {
+		t.Error("Expected reviewer to disallow bash")
+	}
+
+	if registry.Default().Name != DefaultAgentName {
+		t.Errorf("Expected default agent %q, got %q", DefaultAgentName, registry.Default().Name)
+	}
+}
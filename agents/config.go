@@ -0,0 +1,46 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFileName is the agents config file name, expected under
+// ~/.config/bro.
+const ConfigFileName = "agents.yaml"
+
+// DefaultConfigPath returns ~/.config/bro/agents.yaml for the current user.
+func DefaultConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "bro", ConfigFileName), nil
+}
+
+// LoadRegistry builds a Registry from the built-in agents, overridden or
+// extended by any agents defined in the YAML file at path. A missing file
+// is not an error - callers get the built-ins.
+func LoadRegistry(path string) (*Registry, error) {
+	registry := NewDefaultRegistry()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return registry, nil
+		}
+		return nil, err
+	}
+
+	parsed, err := parseAgentsYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for _, agent := range parsed {
+		registry.Register(agent)
+	}
+
+	return registry, nil
+}
@@ -0,0 +1,97 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToolPolicy controls whether a requested tool call executes immediately,
+// requires the user to confirm it in the TUI, or is refused outright.
+type ToolPolicy int
+
+const (
+	// PolicyPrompt asks the user to approve each call before it runs.
+	PolicyPrompt ToolPolicy = iota
+	// PolicyAutoApprove runs the call immediately, with no confirmation.
+	PolicyAutoApprove
+	// PolicyDeny refuses the call without running it.
+	PolicyDeny
+)
+
+func (p ToolPolicy) String() string {
+	switch p {
+	case PolicyAutoApprove:
+		return "auto-approve"
+	case PolicyDeny:
+		return "deny"
+	default:
+		return "prompt"
+	}
+}
+
+// ParsePolicy converts a config/flag value ("auto-approve", "prompt",
+// "deny") into a ToolPolicy.
+func ParsePolicy(value string) (ToolPolicy, error) {
+	switch value {
+	case "auto-approve", "auto", "allow":
+		return PolicyAutoApprove, nil
+	case "prompt", "":
+		return PolicyPrompt, nil
+	case "deny":
+		return PolicyDeny, nil
+	default:
+		return PolicyPrompt, fmt.Errorf("unknown tool policy %q", value)
+	}
+}
+
+// readOnlyTools default to auto-approve: they can't modify files or run
+// commands, so prompting for every call would just add friction.
+var readOnlyTools = map[string]bool{
+	"readfile":   true,
+	"grep":       true,
+	"filefinder": true,
+	"dir_tree":   true,
+	"watcher":    true,
+}
+
+// DefaultToolPolicy is the policy a tool gets when neither the agent nor
+// the session overrides it: read-only tools run immediately, everything
+// else (bash, fileedit, ...) prompts.
+func DefaultToolPolicy(tool string) ToolPolicy {
+	if readOnlyTools[tool] {
+		return PolicyAutoApprove
+	}
+	return PolicyPrompt
+}
+
+// PolicyFor resolves the effective policy for tool: the agent's own
+// ToolPolicies override, if set, otherwise DefaultToolPolicy.
+func (a Agent) PolicyFor(tool string) ToolPolicy {
+	if policy, ok := a.ToolPolicies[tool]; ok {
+		return policy
+	}
+	return DefaultToolPolicy(tool)
+}
+
+// ParsePolicyOverrides parses a flag value like "bash=prompt,fileedit=deny"
+// into a tool-name -> ToolPolicy map, for overriding an agent's policy at
+// session start.
+func ParsePolicyOverrides(value string) (map[string]ToolPolicy, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	overrides := make(map[string]ToolPolicy)
+	for _, pair := range strings.Split(value, ",") {
+		name, policyStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tool policy override %q, expected name=policy", pair)
+		}
+		policy, err := ParsePolicy(strings.TrimSpace(policyStr))
+		if err != nil {
+			return nil, fmt.Errorf("tool %q: %w", strings.TrimSpace(name), err)
+		}
+		overrides[strings.TrimSpace(name)] = policy
+	}
+	return overrides, nil
+}
@@ -0,0 +1,79 @@
+package agents
+
+// DefaultAgentName is used when no agent is selected via -a/--agent or the
+// agents config file.
+const DefaultAgentName = "coder"
+
+// Registry holds the set of agents available to select from, modeled on
+// tools.Registry.
+type Registry struct {
+	agents map[string]Agent
+}
+
+// NewRegistry creates an empty agent registry.
+func NewRegistry() *Registry {
+	return &Registry{agents: make(map[string]Agent)}
+}
+
+// Register adds an agent to the registry, keyed by its Name.
+func (r *Registry) Register(agent Agent) {
+	r.agents[agent.Name] = agent
+}
+
+// Get retrieves an agent by name.
+func (r *Registry) Get(name string) (Agent, bool) {
+	agent, exists := r.agents[name]
+	return agent, exists
+}
+
+// GetAll returns every registered agent.
+func (r *Registry) GetAll() []Agent {
+	var all []Agent
+	for _, agent := range r.agents {
+		all = append(all, agent)
+	}
+	return all
+}
+
+// Default returns the registry's default agent (DefaultAgentName), falling
+// back to a bare built-in-equivalent agent if even that is missing.
+func (r *Registry) Default() Agent {
+	if agent, ok := r.Get(DefaultAgentName); ok {
+		return agent
+	}
+	return NewAgent(DefaultAgentName, "You are a helpful cli assistant", nil, nil)
+}
+
+// builtinAgents returns the out-of-the-box agent set: coder (full
+// toolbox), reviewer (read-only tools), and shell-helper (bash only).
+func builtinAgents() []Agent {
+	return []Agent{
+		NewAgent(
+			"coder",
+			"You are a helpful coding assistant with access to file and shell tools.",
+			nil,
+			nil,
+		),
+		NewAgent(
+			"reviewer",
+			"You are a careful code reviewer. Read and search code, but never modify files or run shell commands.",
+			[]string{"grep", "readfile", "filefinder", "dir_tree", "watcher"},
+			nil,
+		),
+		NewAgent(
+			"shell-helper",
+			"You are a shell assistant. Prefer running commands over explaining them.",
+			[]string{"bash"},
+			nil,
+		),
+	}
+}
+
+// NewDefaultRegistry returns a Registry seeded with the built-in agents.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, agent := range builtinAgents() {
+		r.Register(agent)
+	}
+	return r
+}
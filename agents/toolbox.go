@@ -0,0 +1,62 @@
+package agents
+
+import (
+	"github.com/festeh/bro/lsp"
+	"github.com/festeh/bro/tools"
+	"github.com/festeh/bro/tools/bash"
+	"github.com/festeh/bro/tools/dirtree"
+	"github.com/festeh/bro/tools/fileedit"
+	"github.com/festeh/bro/tools/filefinder"
+	"github.com/festeh/bro/tools/filepatch"
+	"github.com/festeh/bro/tools/grep"
+	"github.com/festeh/bro/tools/modifyfile"
+	"github.com/festeh/bro/tools/readfile"
+	"github.com/festeh/bro/tools/watcher"
+)
+
+// BuildToolRegistry constructs the tool registry scoped to agent's allowed
+// tools. It registers from the same standard toolset openrouter.NewClient
+// otherwise defaults to, then drops whatever the agent doesn't allow.
+// outputPolicy overrides tools.DefaultOutputPolicy for every tool that
+// bounds its output through tools.TruncateResult (bash, filefinder, grep,
+// readfile); a zero outputPolicy leaves the default in place.
+func BuildToolRegistry(agent Agent, outputPolicy tools.OutputPolicy) *tools.Registry {
+	registry := tools.NewRegistry()
+
+	// lspManager is shared across the lsp_* tools below so they reuse the
+	// same spawned language servers and open-file cache instead of each
+	// launching their own.
+	lspManager := lsp.NewManager()
+
+	// toolCtx is shared by every filesystem-traversal tool so they all
+	// apply the same .broignore/.gitignore/binary-file rules (see
+	// selection.New), and by bash/filefinder/grep/readfile so they share one
+	// OutputCache - a readfile output_id lookup only finds what another
+	// tool's TruncateResult call stored if both read from the same cache.
+	toolCtx := tools.DefaultToolContext()
+	toolCtx.OutputPolicy = outputPolicy
+
+	for _, tool := range []tools.Tool{
+		bash.NewToolWithContext(toolCtx),
+		dirtree.NewTool(),
+		fileedit.NewTool(),
+		filefinder.NewToolWithContext(toolCtx),
+		filepatch.NewTool(),
+		grep.NewToolWithContext(toolCtx),
+		modifyfile.NewTool(),
+		readfile.NewToolWithContext(toolCtx),
+		watcher.NewToolWithContext(toolCtx),
+		lsp.NewDefinitionTool(lspManager),
+		lsp.NewReferencesTool(lspManager),
+		lsp.NewHoverTool(lspManager),
+		lsp.NewRenameTool(lspManager),
+		lsp.NewDiagnosticsTool(lspManager),
+		lsp.NewSymbolsTool(lspManager),
+	} {
+		if agent.AllowsTool(tool.Name()) {
+			registry.Register(tool)
+		}
+	}
+
+	return registry
+}
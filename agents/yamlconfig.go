@@ -0,0 +1,58 @@
+package agents
+
+import "gopkg.in/yaml.v3"
+
+// yamlAgentsFile is agents.yaml's on-disk shape.
+type yamlAgentsFile struct {
+	Agents []yamlAgent `yaml:"agents"`
+}
+
+// yamlAgent mirrors Agent with yaml tags. ToolPolicies is a tool name ->
+// policy string map ("prompt"/"auto-approve"/"deny") on disk;
+// parseAgentsYAML converts it to Agent.ToolPolicies via ParsePolicy.
+type yamlAgent struct {
+	Name         string            `yaml:"name"`
+	SystemPrompt string            `yaml:"system_prompt,omitempty"`
+	AllowedTools []string          `yaml:"allowed_tools,omitempty"`
+	PreloadFiles []string          `yaml:"preload_files,omitempty"`
+	ToolPolicies map[string]string `yaml:"tool_policies,omitempty"`
+	Provider     string            `yaml:"provider,omitempty"`
+	Model        string            `yaml:"model,omitempty"`
+}
+
+// parseAgentsYAML parses the agents config file - a top-level "agents:"
+// list - via yaml.v3.
+func parseAgentsYAML(data []byte) ([]Agent, error) {
+	var file yamlAgentsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	parsed := make([]Agent, 0, len(file.Agents))
+	for _, a := range file.Agents {
+		agent := Agent{
+			Name:         a.Name,
+			SystemPrompt: a.SystemPrompt,
+			AllowedTools: a.AllowedTools,
+			PreloadFiles: a.PreloadFiles,
+			Provider:     a.Provider,
+			Model:        a.Model,
+		}
+
+		if len(a.ToolPolicies) > 0 {
+			agent.ToolPolicies = make(map[string]ToolPolicy, len(a.ToolPolicies))
+			for tool, value := range a.ToolPolicies {
+				// Unknown policy values are skipped rather than failing the
+				// whole config, consistent with the rest of agents.yaml
+				// parsing.
+				if policy, err := ParsePolicy(value); err == nil {
+					agent.ToolPolicies[tool] = policy
+				}
+			}
+		}
+
+		parsed = append(parsed, agent)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,53 @@
+package agents
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAgentsYAML(t *testing.T) {
+	input := `agents:
+  - name: coder
+    system_prompt: "You are a coder"
+    allowed_tools: [bash, fileedit]
+  - name: reviewer
+    system_prompt: "You are a reviewer"
+    allowed_tools: [grep, readfile]
+    preload_files: [README.md]
+`
+
+	parsed, err := parseAgentsYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("parseAgentsYAML failed: %v", err)
+	}
+
+	if len(parsed) != 2 {
+		t.Fatalf("Expected 2 agents, got %d", len(parsed))
+	}
+
+	coder := parsed[0]
+	if coder.Name != "coder" {
+		t.Errorf("Expected name 'coder', got %q", coder.Name)
+	}
+	if coder.SystemPrompt != "You are a coder" {
+		t.Errorf("Expected system prompt 'You are a coder', got %q", coder.SystemPrompt)
+	}
+	if !reflect.DeepEqual(coder.AllowedTools, []string{"bash", "fileedit"}) {
+		t.Errorf("Expected allowed_tools [bash fileedit], got %v", coder.AllowedTools)
+	}
+
+	reviewer := parsed[1]
+	if !reflect.DeepEqual(reviewer.PreloadFiles, []string{"README.md"}) {
+		t.Errorf("Expected preload_files [README.md], got %v", reviewer.PreloadFiles)
+	}
+}
+
+func TestParseAgentsYAMLEmpty(t *testing.T) {
+	parsed, err := parseAgentsYAML([]byte("agents:\n"))
+	if err != nil {
+		t.Fatalf("parseAgentsYAML failed: %v", err)
+	}
+	if len(parsed) != 0 {
+		t.Errorf("Expected no agents, got %d", len(parsed))
+	}
+}
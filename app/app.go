@@ -1,16 +1,21 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
 	"github.com/festeh/bro/config"
 	"github.com/festeh/bro/environment"
 	"github.com/festeh/bro/openrouter"
+	"github.com/festeh/bro/provider"
+	"github.com/festeh/bro/store"
 	"github.com/festeh/bro/tools"
 )
 
@@ -32,13 +37,65 @@ type App struct {
 	currentResponse  string
 	pendingToolCalls []openrouter.ToolCall
 	isWaiting        bool
-	client           *openrouter.Client
+	client           provider.ChatCompletionProvider
 	eventChan        chan tea.Msg
 	scrollOffset     int // For scrolling through message history
 	mode             string
 	config           config.Config
+	agent            agents.Agent
 	historyIndex     int    // Current position in command history (-1 means not navigating)
 	originalInput    string // Store original input when navigating history
+
+	// Tool confirmation gate (see toolconfirm.go): toolCallQueue holds this
+	// turn's tool calls awaiting execution, awaitingTool is the one
+	// currently blocked on a y/n/a keypress (mode == "confirm"), and
+	// toolPolicies holds session "always allow" overrides on top of the
+	// agent's own per-tool policy.
+	toolCallQueue    []openrouter.ToolCall
+	awaitingTool     *openrouter.ToolCall
+	toolPolicies     map[string]agents.ToolPolicy
+	toolBatchPending bool
+
+	// runningTool, toolCancel, and toolProgress track the tool call
+	// currently executing in the background while a.mode == "tool-running"
+	// (see runToolCall in toolconfirm.go): runningTool and toolProgress
+	// drive the View's status line, and toolCancel lets a ctrl+c keypress
+	// cancel the call instead of quitting the whole app.
+	runningTool  *openrouter.ToolCall
+	toolCancel   context.CancelFunc
+	toolProgress tools.ProgressUpdate
+
+	// toolIteration counts tool-result round trips within the current user
+	// turn, reset to 0 each time a new user message is sent; see
+	// finishToolExecution for the bound it's checked against.
+	toolIteration int
+
+	// branches, branchIndex, and editingMessage back edit-and-resend (see
+	// branch.go): branches holds one turnBranch snapshot per resend attempt
+	// at the most recent user turn, branchIndex is the one currently
+	// loaded into a.messages, and editingMessage is set by ctrl+e to tell
+	// the next "enter" to resend as a sibling branch instead of just
+	// appending.
+	branches       []turnBranch
+	branchIndex    int
+	editingMessage bool
+
+	// renderCache memoizes renderMessage's wrapped, syntax-highlighted
+	// output per message index (see render.go), so View/calculateTotalLines
+	// only re-wrap and re-run glamour over a message when its content or
+	// the chat width actually changed, instead of on every keystroke.
+	renderCache map[int]renderCacheEntry
+
+	// turnStartTime, turnCharCount, turnTokenCount, and turnCost back the
+	// input footer's streaming metrics (see metrics.go's streamingStatus):
+	// reset by resetTurnMetrics at the start of each user turn,
+	// turnCharCount grows with every streamChunkMsg as a tokens/sec
+	// approximation until a streamUsageMsg arrives with OpenRouter's own
+	// completion token count and cost.
+	turnStartTime  time.Time
+	turnCharCount  int
+	turnTokenCount int
+	turnCost       float64
 }
 
 func NewApp() App {
@@ -50,27 +107,36 @@ func NewApp() App {
 	return NewAppWithConfig(*appConfig)
 }
 
+// NewAppWithConfig builds an App running the default agent (see
+// agents.DefaultAgentName), with the full toolbox available.
 func NewAppWithConfig(appConfig config.Config) App {
+	return NewAppWithAgent(appConfig, agents.NewDefaultRegistry().Default())
+}
+
+// NewAppWithAgent builds an App scoped to agent: its system prompt and its
+// toolbox, instead of the full tool registry and a hardcoded prompt.
+func NewAppWithAgent(appConfig config.Config, agent agents.Agent) App {
+	return NewAppWithPolicies(appConfig, agent, nil)
+}
+
+// NewAppWithPolicies builds an App scoped to agent, additionally overriding
+// its tool confirmation policies for this session (e.g. from a CLI flag).
+// toolPolicyOverrides may be nil, in which case the agent's own
+// agents.Agent.PolicyFor resolution applies unchanged.
+func NewAppWithPolicies(appConfig config.Config, agent agents.Agent, toolPolicyOverrides map[string]agents.ToolPolicy) App {
 	env, err := environment.NewEnvironment()
 	if err != nil {
 		log.Error("Failed to initialize environment", "error", err)
 		return App{}
 	}
 
-	openrouterConfig := &openrouter.Config{
-		// Model: "qwen/qwen3-coder",
-		// Model: "anthropic/claude-sonnet-4",
-		// Model: "x-ai/grok-4",
-		Model: "z-ai/glm-4.5",
-	}
-
-	client, err := openrouter.NewClient(env, openrouterConfig)
+	client, err := NewProvider(appConfig, agent, env)
 	if err != nil {
-		log.Error("Failed to initialize OpenRouter client", "error", err)
+		log.Error("Failed to initialize chat completion provider", "error", err)
 		return App{}
 	}
 
-	systemPrompt := GenerateSystemPrompt()
+	systemPrompt := buildSystemPrompt(agent)
 	initialMessages := []openrouter.Renderable{
 		openrouter.NewSystemMessage(systemPrompt),
 	}
@@ -82,14 +148,79 @@ func NewAppWithConfig(appConfig config.Config) App {
 		eventChan:    make(chan tea.Msg, EVENT_CHAN_BUFFER),
 		mode:         "chat",
 		config:       appConfig,
+		agent:        agent,
 		historyIndex: -1,
+		toolPolicies: toolPolicyOverrides,
+		renderCache:  make(map[int]renderCacheEntry),
+	}
+}
+
+// NewAppWithResume builds an App the same way NewAppWithPolicies does, but
+// seeds its message history from conversationID's active branch (see
+// store.Branch) instead of starting fresh. The caller falls back to
+// NewAppWithPolicies if this returns an error, e.g. an unknown conversation
+// id.
+func NewAppWithResume(appConfig config.Config, agent agents.Agent, toolPolicyOverrides map[string]agents.ToolPolicy, conversationID string) (App, error) {
+	path, err := store.DefaultPath()
+	if err != nil {
+		return App{}, err
+	}
+	s, err := store.Open(path)
+	if err != nil {
+		return App{}, err
+	}
+	defer s.Close()
+
+	branch, err := s.Branch(conversationID)
+	if err != nil {
+		return App{}, fmt.Errorf("loading conversation %q: %w", conversationID, err)
+	}
+
+	a := NewAppWithPolicies(appConfig, agent, toolPolicyOverrides)
+	if a.client == nil {
+		return App{}, fmt.Errorf("failed to initialize app")
 	}
+
+	messages := []openrouter.Renderable{openrouter.NewSystemMessage(buildSystemPrompt(agent))}
+	for _, msg := range branch {
+		switch msg.Role {
+		case "user":
+			messages = append(messages, openrouter.NewUserMessage(msg.Content))
+		case "assistant":
+			messages = append(messages, openrouter.NewAssistantMessage(msg.Content, a.client.GetModel()))
+		}
+	}
+	a.SetMessages(messages)
+
+	return a, nil
 }
 
 func (a *App) SetMessages(messages []openrouter.Renderable) {
 	a.messages = messages
 }
 
+// ResumeFromSession replaces a's message history (keeping its system
+// prompt) with the transcript replayed from a prior session log at path
+// (see config.LoadSession), and restores any edit-and-resend branches the
+// session had (see restoreBranches) so ctrl+left/ctrl+right still work
+// after a restart. It's the shared implementation behind both the /resume
+// command (app/commands.go) and the --resume-session launch flag, and
+// returns how many messages were replayed.
+func (a *App) ResumeFromSession(path string) (int, error) {
+	messages, branches, err := config.LoadSession(path)
+	if err != nil {
+		return 0, err
+	}
+
+	systemMsg := openrouter.NewSystemMessage(buildSystemPrompt(a.agent))
+	rebuilt := []openrouter.Renderable{systemMsg}
+	rebuilt = append(rebuilt, messages...)
+	a.SetMessages(rebuilt)
+	a.restoreBranches(systemMsg, branches)
+
+	return len(messages), nil
+}
+
 func (a App) Init() tea.Cmd {
 	return a.listenForEvents()
 }
@@ -104,6 +235,7 @@ type streamChunkMsg string
 type streamDoneMsg struct{}
 type streamErrorMsg error
 type streamToolCallMsg openrouter.StreamEvent
+type streamUsageMsg openrouter.StreamEvent
 
 func (a App) streamCompletions() tea.Cmd {
 	return func() tea.Msg {
@@ -118,6 +250,8 @@ func (a App) streamCompletions() tea.Cmd {
 				a.eventChan <- streamErrorMsg(event.Error)
 			case openrouter.StreamEventToolCall:
 				a.eventChan <- streamToolCallMsg(event)
+			case openrouter.StreamEventUsage:
+				a.eventChan <- streamUsageMsg(event)
 			}
 		})
 		if err != nil {
@@ -133,6 +267,19 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 	case tea.KeyMsg:
+		if a.mode == "confirm" {
+			if msg.String() == "ctrl+c" {
+				return a, tea.Quit
+			}
+			a.resolveAwaitingTool(msg.String())
+			return a, nil
+		}
+		if a.mode == "tool-running" {
+			if msg.String() == "ctrl+c" && a.toolCancel != nil {
+				a.toolCancel()
+			}
+			return a, nil
+		}
 		if a.mode == "help" {
 			if msg.String() == "q" {
 				a.mode = "chat"
@@ -160,32 +307,53 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.navigateHistoryUp()
 		case "down":
 			a.navigateHistoryDown()
+		case "ctrl+e":
+			if a.beginEditLastMessage() {
+				a.editingMessage = true
+			}
+		case "ctrl+left":
+			a.switchBranch(-1)
+		case "ctrl+right":
+			a.switchBranch(1)
 		case "enter":
 			if strings.TrimSpace(a.input) != "" && !a.isWaiting && a.client != nil {
 				trimmed := strings.TrimSpace(a.input)
-				
+
 				// Add command to history
 				if err := a.config.History.AddCommand(trimmed); err != nil {
 					log.Error("Failed to add command to history", "error", err)
 				}
-				
+
+				if a.editingMessage {
+					if err := a.config.Session.LogBranchReset(len(a.messages) - 1); err != nil {
+						log.Error("Failed to log branch reset to session", "error", err)
+					}
+				}
+
 				// Log user input to session
 				if err := a.config.Session.LogUserInput(trimmed); err != nil {
 					log.Error("Failed to log user input to session", "error", err)
 				}
-				
+
 				// Reset history navigation
 				a.historyIndex = -1
 				a.originalInput = ""
-				
+
 				if a.handleUserCommand(trimmed) {
 					return a, nil
 				}
 				userMsg := openrouter.NewUserMessage(a.input)
-				a.messages = append(a.messages, userMsg)
+				if a.editingMessage {
+					a.resendEditedMessage(userMsg)
+					a.editingMessage = false
+				} else {
+					a.messages = append(a.messages, userMsg)
+				}
 				a.currentResponse = ""
 				a.isWaiting = true
 				a.scrollOffset = 0
+				a.toolIteration = 0
+				a.resetTurnMetrics()
 
 				a.input = ""
 
@@ -206,13 +374,14 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case streamChunkMsg:
 		a.currentResponse += string(msg)
+		a.trackChunk(string(msg))
 		return a, a.listenForEvents()
 	case streamDoneMsg:
 		// Add the AI response first
 		if a.currentResponse != "" {
 			trimmedResponse := strings.TrimSpace(a.currentResponse)
-			a.messages = append(a.messages, openrouter.NewAssistantMessage(trimmedResponse))
-			
+			a.messages = append(a.messages, openrouter.NewAssistantMessage(trimmedResponse, a.client.GetModel()))
+
 			// Log AI response with tool calls to session
 			toolCallsForLogging := make([]interface{}, len(a.pendingToolCalls))
 			for i, toolCall := range a.pendingToolCalls {
@@ -228,39 +397,17 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		// Then execute any pending tool calls in order
-		for _, toolCall := range a.pendingToolCalls {
-			log.Info("Executing tool call: %v", toolCall)
-			// Add tool call message
-			toolCallMsg := &openrouter.ToolCallMessage{ToolCall: toolCall}
-			a.messages = append(a.messages, toolCallMsg)
-
-			// Execute tool and add response message
-			result, err := tools.ExecuteTool(a.client.GetToolRegistry(), toolCall.Function.Name, []byte(toolCall.Function.Arguments))
-			toolResponseMsg := &openrouter.ToolResponseMessage{
-				ToolCallID: toolCall.ID,
-				ToolName:   toolCall.Function.Name,
-				Result:     result,
-				Error:      err,
-			}
-			a.messages = append(a.messages, toolResponseMsg)
-			
-			// Log tool call to session
-			if err := a.config.Session.LogToolCall(toolCall.Function.Name, toolCall.Function.Arguments, result); err != nil {
-				log.Error("Failed to log tool call to session", "error", err)
-			}
-		}
-
-		if len(a.pendingToolCalls) > 0 {
-			log.Info("Send tool call results")
-			go a.streamCompletions()()
-		} else {
-			log.Info("No tool calls to execute")
+		// Queue pending tool calls for confirmation-gated execution: each
+		// runs, prompts, or is denied according to a.policyFor, in order.
+		a.toolCallQueue = a.pendingToolCalls
+		a.toolBatchPending = len(a.pendingToolCalls) > 0
+		if a.advanceToolQueue() {
+			a.finishToolExecution()
 		}
 		a.resetToBottom()
 		return a, a.listenForEvents()
 	case streamErrorMsg:
-		a.messages = append(a.messages, openrouter.NewAssistantMessage(fmt.Sprintf("Error: %v", msg)))
+		a.messages = append(a.messages, openrouter.NewAssistantMessage(fmt.Sprintf("Error: %v", msg), a.client.GetModel()))
 		a.resetToBottom()
 		return a, a.listenForEvents()
 	case streamToolCallMsg:
@@ -281,23 +428,19 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return a, a.listenForEvents()
+	case streamUsageMsg:
+		a.trackUsage(openrouter.StreamEvent(msg))
+		return a, a.listenForEvents()
+	case toolProgressMsg:
+		a.toolProgress = tools.ProgressUpdate(msg)
+		return a, a.listenForEvents()
+	case toolResultMsg:
+		a.handleToolResult(msg)
+		return a, a.listenForEvents()
 	}
 	return a, nil
 }
 
-func (a App) calculateLinesFromContent(content string, chatWidth int) int {
-	lines := strings.Split(content, "\n")
-	totalLines := 0
-	for _, line := range lines {
-		if len(line) == 0 {
-			totalLines += 1 // Empty lines take 1 screen line
-		} else {
-			totalLines += (len(line) + chatWidth - 1) / chatWidth // Ceiling division
-		}
-	}
-	return totalLines
-}
-
 func (a App) calculateTotalLines() int {
 	_, chatWidth, _ := a.getChatDimensions()
 	if chatWidth <= 0 {
@@ -305,15 +448,13 @@ func (a App) calculateTotalLines() int {
 	}
 
 	totalLines := 0
-	for _, msg := range a.messages {
-		rendered := msg.Render()
-		totalLines += a.calculateLinesFromContent(rendered, chatWidth)
+	for i, msg := range a.messages {
+		totalLines += len(a.renderMessage(i, msg, chatWidth))
 	}
 
 	if a.currentResponse != "" {
-		currentMsg := openrouter.NewAssistantMessage(a.currentResponse)
-		rendered := currentMsg.Render()
-		totalLines += a.calculateLinesFromContent(rendered, chatWidth)
+		currentMsg := openrouter.NewAssistantMessage(a.currentResponse, a.client.GetModel())
+		totalLines += len(wrapContent(currentMsg.Render(), chatWidth))
 	}
 
 	return totalLines
@@ -327,12 +468,12 @@ func (a *App) resetToBottom() {
 }
 
 func (a *App) navigateHistoryUp() {
-	
+
 	commands := a.config.History.GetCommands()
 	if len(commands) == 0 {
 		return
 	}
-	
+
 	if a.historyIndex == -1 {
 		// Starting history navigation, save current input
 		a.originalInput = a.input
@@ -340,7 +481,7 @@ func (a *App) navigateHistoryUp() {
 	} else if a.historyIndex > 0 {
 		a.historyIndex--
 	}
-	
+
 	if a.historyIndex >= 0 && a.historyIndex < len(commands) {
 		a.input = commands[a.historyIndex]
 	}
@@ -350,7 +491,7 @@ func (a *App) navigateHistoryDown() {
 	if a.historyIndex == -1 {
 		return
 	}
-	
+
 	commands := a.config.History.GetCommands()
 	if a.historyIndex < len(commands)-1 {
 		a.historyIndex++
@@ -397,42 +538,27 @@ func (a App) View() string {
 		chatContent = "No messages yet. Start typing below!"
 	} else {
 
-		// Build all content lines
-		var allLines []string
-		for _, msg := range a.messages {
-			rendered := msg.Render()
-			lines := strings.Split(rendered, "\n")
-			allLines = append(allLines, lines...)
+		// Build all content lines, already word- and rune-wrapped by
+		// renderMessage/wrapContent (see render.go) - each historical
+		// message comes out of a's per-(index, width) cache, so a
+		// keystroke that doesn't change chatWidth only re-wraps the
+		// in-flight response below, not the whole transcript.
+		var allWrappedLines []string
+		for i, msg := range a.messages {
+			allWrappedLines = append(allWrappedLines, a.renderMessage(i, msg, chatWidth)...)
 		}
 
-		// Add current response if present
+		// Add current response if present; it changes every chunk, so
+		// there's no point caching it.
 		if a.currentResponse != "" {
-			currentMsg := openrouter.NewAssistantMessage(a.currentResponse)
-			rendered := currentMsg.Render()
-			lines := strings.Split(rendered, "\n")
+			currentMsg := openrouter.NewAssistantMessage(a.currentResponse, a.client.GetModel())
+			lines := wrapContent(currentMsg.Render(), chatWidth)
 			for i, line := range lines {
 				content := line
 				if a.isWaiting && i == len(lines)-1 {
 					content += CURSOR_CHAR
 				}
-				allLines = append(allLines, content)
-			}
-		}
-
-		// First wrap all lines to get complete wrapped content
-		var allWrappedLines []string
-		for _, line := range allLines {
-			if len(line) <= chatWidth {
-				allWrappedLines = append(allWrappedLines, line)
-			} else {
-				// Wrap long lines
-				for len(line) > chatWidth {
-					allWrappedLines = append(allWrappedLines, line[:chatWidth])
-					line = line[chatWidth:]
-				}
-				if len(line) > 0 {
-					allWrappedLines = append(allWrappedLines, line)
-				}
+				allWrappedLines = append(allWrappedLines, content)
 			}
 		}
 
@@ -466,11 +592,20 @@ func (a App) View() string {
 	chat := chatStyle.Render(chatContent)
 	input := inputStyle.Render(fmt.Sprintf("> %s", a.input))
 	help := ""
+	if a.mode == "tool-running" && a.runningTool != nil {
+		help = fmt.Sprintf("Running %s... (%d lines, %d bytes) - ctrl+c to cancel\n%s",
+			a.runningTool.Function.Name, a.toolProgress.Lines, a.toolProgress.Bytes, a.toolProgress.Line)
+	}
+
+	metrics := ""
+	if a.isWaiting {
+		metrics = a.streamingStatus()
+	}
 
 	// Debug info
 	totalLines := a.calculateTotalLines()
 	debug := fmt.Sprintf("Debug: offset=%d, totalLines=%d, maxLines=%d",
 		a.scrollOffset, totalLines, maxLines)
 
-	return lipgloss.JoinVertical(lipgloss.Left, chat, input, help, debug)
+	return lipgloss.JoinVertical(lipgloss.Left, chat, input, metrics, help, debug)
 }
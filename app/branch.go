@@ -0,0 +1,118 @@
+package app
+
+import (
+	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/openrouter"
+)
+
+// turnBranch is a snapshot of a.messages taken when an edited user message
+// is resent (see resendEditedMessage), so ctrl+left/ctrl+right can switch
+// back to an earlier attempt at the same turn instead of the edit silently
+// overwriting it the way history navigation overwrites a.input.
+type turnBranch struct {
+	messages []openrouter.Renderable
+}
+
+// lastUserMessageIndex returns the index of the most recently sent message
+// in a.messages that IsUser(), or -1 if there isn't one yet.
+func (a *App) lastUserMessageIndex() int {
+	for i := len(a.messages) - 1; i >= 0; i-- {
+		if a.messages[i].IsUser() {
+			return i
+		}
+	}
+	return -1
+}
+
+// beginEditLastMessage implements ctrl+e: pulls the most recently sent user
+// message's text back into the input box and truncates it - and everything
+// after it, i.e. the assistant's reply and any tool calls - out of
+// a.messages, so the next "enter" resends it as a fresh turn instead of
+// appending a duplicate. It reports whether an edit actually started; it's
+// a no-op (returning false) while a response is in flight or there's no
+// user message yet to edit.
+func (a *App) beginEditLastMessage() bool {
+	if a.isWaiting {
+		return false
+	}
+	index := a.lastUserMessageIndex()
+	if index == -1 {
+		return false
+	}
+	msg, ok := a.messages[index].(*openrouter.ChatMessage)
+	if !ok {
+		return false
+	}
+
+	if len(a.branches) == 0 {
+		a.branches = []turnBranch{{messages: cloneMessages(a.messages)}}
+		a.branchIndex = 0
+	} else {
+		a.branches[a.branchIndex].messages = cloneMessages(a.messages)
+	}
+
+	a.input = msg.Content.Text
+	a.messages = cloneMessages(a.messages[:index])
+	return true
+}
+
+// resendEditedMessage implements the "enter" half of edit-and-resend: the
+// "enter" key handler calls it instead of plain appending once
+// beginEditLastMessage has truncated a.messages earlier in this turn. It
+// records the truncated slate plus the new message as a fresh turnBranch,
+// sibling to whatever was there before, so switchBranch can still reach the
+// original.
+func (a *App) resendEditedMessage(userMsg openrouter.Renderable) {
+	a.messages = append(a.messages, userMsg)
+	a.branches = append(a.branches, turnBranch{messages: cloneMessages(a.messages)})
+	a.branchIndex = len(a.branches) - 1
+}
+
+// switchBranch implements ctrl+left (delta -1) and ctrl+right (delta +1):
+// it saves a.messages into the currently selected branch - in case an
+// assistant reply was appended to it since it was recorded - before moving
+// to and loading delta's neighbor. It's a no-op with fewer than two
+// branches (nothing has been edited-and-resent yet) or while a response is
+// in flight.
+func (a *App) switchBranch(delta int) {
+	if len(a.branches) < 2 || a.isWaiting {
+		return
+	}
+
+	a.branches[a.branchIndex].messages = cloneMessages(a.messages)
+
+	next := a.branchIndex + delta
+	if next < 0 || next >= len(a.branches) {
+		return
+	}
+	a.branchIndex = next
+	a.messages = cloneMessages(a.branches[a.branchIndex].messages)
+}
+
+// restoreBranches seeds a.branches/a.branchIndex from the sibling
+// snapshots config.LoadSession recovered from a prior session log's
+// "branch_reset" entries - the session log already records everything
+// beginEditLastMessage/resendEditedMessage need, it just wasn't being read
+// back into a.branches, so edit-and-resend history vanished the moment the
+// process exited even though it was sitting in the log the whole time.
+// systemMsg is prepended to each restored branch to match the shape
+// beginEditLastMessage's own snapshots have (a.messages always starts with
+// the system prompt). A no-op for a session with no edits to restore.
+func (a *App) restoreBranches(systemMsg openrouter.Renderable, branches []config.SessionBranch) {
+	if len(branches) == 0 {
+		return
+	}
+
+	a.branches = make([]turnBranch, len(branches))
+	for i, b := range branches {
+		messages := append([]openrouter.Renderable{systemMsg}, b.Messages...)
+		a.branches[i] = turnBranch{messages: messages}
+	}
+	a.branchIndex = len(a.branches) - 1
+}
+
+func cloneMessages(messages []openrouter.Renderable) []openrouter.Renderable {
+	cloned := make([]openrouter.Renderable, len(messages))
+	copy(cloned, messages)
+	return cloned
+}
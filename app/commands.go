@@ -2,13 +2,16 @@ package app
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/festeh/bro/agents"
 	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/environment"
 	"github.com/festeh/bro/openrouter"
 )
 
-
 func (a *App) handleUserCommand(input string) bool {
 	if !strings.HasPrefix(input, "/") {
 		return false
@@ -40,23 +43,213 @@ func (a *App) handleUserCommand(input string) bool {
 		if modelName == "" {
 			currentModel := a.client.GetModel()
 			a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Current model: %s", currentModel)))
+		} else if providerName, name, ok := splitProviderModel(modelName, a.config); ok {
+			a.handleModelSwitchCommand(providerName, name)
 		} else {
-			if a.config == nil || len(a.config.AvailableModels) == 0 {
+			if len(a.config.AvailableModels) == 0 {
 				a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage("Error: Available models not loaded"))
 			} else if !a.config.IsValidModel(modelName) {
 				a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Model '%s' is not available. Available models:\n%s", modelName, strings.Join(a.config.AvailableModels, "\n"))))
 			} else {
-				a.client.SetModel(modelName)
-				a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Model set to: %s", modelName)))
+				resolved := modelName
+				if entry, ok := a.config.ResolveModel(modelName); ok {
+					resolved = entry.Model
+				}
+				a.client.SetModel(resolved)
+				a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Model set to: %s", resolved)))
 			}
 		}
 		a.input = ""
 		return true
 	}
 
+	if strings.HasPrefix(cmd, "agent") {
+		name := strings.TrimSpace(strings.TrimPrefix(cmd, "agent"))
+		if name == "" {
+			a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Current agent: %s", a.agent.Name)))
+		} else {
+			a.handleAgentCommand(name)
+		}
+		a.input = ""
+		return true
+	}
+
+	if strings.HasPrefix(cmd, "resume") {
+		a.handleResumeCommand(strings.TrimSpace(strings.TrimSpace(strings.TrimPrefix(input, "/"))[len("resume"):]))
+		a.input = ""
+		return true
+	}
+
+	if cmd == "sessions" {
+		a.handleSessionsCommand()
+		a.input = ""
+		return true
+	}
+
 	// Command not recognized
 	a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage("Command not recognized: "+input))
 	a.input = ""
 	return true
 }
 
+// handleAgentCommand implements "/agent <name>": switches a's active agent,
+// rebuilding the chat client against the new agent's system prompt and
+// toolbox (see NewProvider) without losing the conversation's message
+// history. name is looked up the same way selectAgent resolves --agent at
+// startup - the bro config directory's agents.yaml, falling back to the
+// built-in registry.
+func (a *App) handleAgentCommand(name string) {
+	configPath, err := agents.DefaultConfigPath()
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to resolve agents config path: %v", err)))
+		return
+	}
+
+	registry, err := agents.LoadRegistry(configPath)
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to load agents config: %v", err)))
+		return
+	}
+
+	agent, ok := registry.Get(name)
+	if !ok {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Unknown agent %q", name)))
+		return
+	}
+
+	env, err := environment.NewEnvironment()
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to initialize environment: %v", err)))
+		return
+	}
+
+	client, err := NewProvider(a.config, agent, env)
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to switch agent: %v", err)))
+		return
+	}
+
+	a.agent = agent
+	a.client = client
+	if len(a.messages) > 0 {
+		a.messages[0] = openrouter.NewSystemMessage(buildSystemPrompt(agent))
+	}
+	a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Switched to agent: %s", agent.Name)))
+}
+
+// knownProviders names every backend NewProvider (app/provider.go) knows
+// how to build a client for, used by splitProviderModel to recognize
+// "/model <provider>/<name>" without misreading an openrouter model id's
+// own "vendor/model" convention (e.g. "anthropic/claude-sonnet-4") as a
+// backend switch.
+var knownProviders = map[string]bool{
+	"openrouter": true,
+	"ollama":     true,
+	"anthropic":  true,
+	"openai":     true,
+	"gemini":     true,
+}
+
+// splitProviderModel recognizes "/model <provider>/<name>"'s backend-switch
+// shape: it only fires when modelName's prefix names a knownProviders
+// entry AND modelName isn't already a configured openrouter alias or model
+// id, so plain "/model anthropic/claude-sonnet-4" (an openrouter model)
+// keeps working exactly as before.
+func splitProviderModel(modelName string, cfg config.Config) (providerName, model string, ok bool) {
+	prefix, rest, found := strings.Cut(modelName, "/")
+	if !found || rest == "" || !knownProviders[prefix] {
+		return "", "", false
+	}
+	if _, configured := cfg.ResolveModel(modelName); configured || cfg.IsValidModel(modelName) {
+		return "", "", false
+	}
+	return prefix, rest, true
+}
+
+// handleModelSwitchCommand implements the backend-switching half of
+// "/model <provider>/<name>": it rebuilds a's chat client the way
+// handleAgentCommand does for "/agent <name>", but by overriding the
+// active agent's own Provider/Model instead of switching agents entirely.
+func (a *App) handleModelSwitchCommand(providerName, model string) {
+	env, err := environment.NewEnvironment()
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to initialize environment: %v", err)))
+		return
+	}
+
+	agent := a.agent
+	agent.Provider = providerName
+	agent.Model = model
+
+	client, err := NewProvider(a.config, agent, env)
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to switch model: %v", err)))
+		return
+	}
+
+	a.agent = agent
+	a.client = client
+	a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Model set to: %s/%s", providerName, model)))
+}
+
+// handleResumeCommand implements "/resume [file]". With no argument, it
+// resumes the most recent session log across every day directory. A bare
+// filename (no path separator) is resolved against today's session
+// directory, a relative path with a day directory in it - the
+// "15Jul_2026/14_30.jsonl" form /sessions lists - against ~/.bro, and an
+// absolute path is used as-is.
+func (a *App) handleResumeCommand(arg string) {
+	path := arg
+	if path == "" {
+		recent, err := config.MostRecentSessionFile()
+		if err != nil {
+			a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to find a session to resume: %v", err)))
+			return
+		}
+		if recent == "" {
+			a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage("No prior sessions found"))
+			return
+		}
+		path = recent
+	} else if !filepath.IsAbs(path) {
+		if !strings.ContainsRune(path, os.PathSeparator) {
+			path = filepath.Join(a.config.Session.Dir(), path)
+		} else if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".bro", path)
+		}
+	}
+
+	n, err := a.ResumeFromSession(path)
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to resume %q: %v", path, err)))
+		return
+	}
+	a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf("Resumed %d messages from %s", n, path)))
+}
+
+// handleSessionsCommand implements "/sessions": list today's and prior
+// days' session log files, oldest first.
+func (a *App) handleSessionsCommand() {
+	days, err := config.ListSessionDays()
+	if err != nil {
+		a.messages = append(a.messages, openrouter.NewCommandErrorResponseMessage(fmt.Sprintf("Failed to list sessions: %v", err)))
+		return
+	}
+
+	var lines []string
+	for _, day := range days {
+		files, err := config.ListSessionFiles(day)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			lines = append(lines, fmt.Sprintf("%s/%s", day, filepath.Base(f)))
+		}
+	}
+
+	if len(lines) == 0 {
+		a.messages = append(a.messages, openrouter.NewCommandResponseMessage("No prior sessions found"))
+		return
+	}
+	a.messages = append(a.messages, openrouter.NewCommandResponseMessage("Sessions:\n"+strings.Join(lines, "\n")))
+}
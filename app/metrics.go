@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/festeh/bro/openrouter"
+)
+
+// approxCharsPerToken is a rough characters-per-token heuristic (English
+// prose averages around 4 characters per GPT-style token), used to
+// estimate tokens/sec live from streamed chunk content until the turn's
+// StreamEventUsage arrives with OpenRouter's actual completion token count.
+const approxCharsPerToken = 4
+
+// resetTurnMetrics clears the token/cost/elapsed tracking behind
+// streamingStatus, called whenever a new user turn starts (alongside the
+// existing toolIteration reset).
+func (a *App) resetTurnMetrics() {
+	a.turnStartTime = time.Now()
+	a.turnCharCount = 0
+	a.turnTokenCount = 0
+	a.turnCost = 0
+}
+
+// trackChunk records content arriving as part of the current turn's
+// streamed response, used to approximate the token count until a
+// streamUsageMsg arrives with the real one.
+func (a *App) trackChunk(content string) {
+	a.turnCharCount += len(content)
+}
+
+// trackUsage records OpenRouter's own token and cost accounting for the
+// current turn once the stream reports it (event.Usage), overriding the
+// running character-based approximation with the real completion token
+// count.
+func (a *App) trackUsage(event openrouter.StreamEvent) {
+	if event.Usage == nil {
+		return
+	}
+	a.turnTokenCount = event.Usage.CompletionTokens
+	a.turnCost = event.Usage.Cost
+}
+
+// streamingStatus renders the input footer's streaming metrics line -
+// "tokens=N (X tok/s) elapsed=Ys $0.0012" - shown while a turn is in
+// flight. It's "" once resetTurnMetrics hasn't run yet (no turn started).
+func (a *App) streamingStatus() string {
+	if a.turnStartTime.IsZero() {
+		return ""
+	}
+
+	tokens := a.turnTokenCount
+	if tokens == 0 {
+		tokens = a.turnCharCount / approxCharsPerToken
+	}
+
+	seconds := time.Since(a.turnStartTime).Seconds()
+	tokensPerSec := 0.0
+	if seconds > 0 {
+		tokensPerSec = float64(tokens) / seconds
+	}
+
+	status := fmt.Sprintf("tokens=%d (%.1f tok/s) elapsed=%.1fs", tokens, tokensPerSec, seconds)
+	if a.turnCost > 0 {
+		status += fmt.Sprintf(" $%.4f", a.turnCost)
+	}
+	return status
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/environment"
+	"github.com/festeh/bro/openrouter"
+	"github.com/festeh/bro/provider"
+	"github.com/festeh/bro/provider/anthropic"
+	"github.com/festeh/bro/provider/gemini"
+	"github.com/festeh/bro/provider/ollama"
+	"github.com/festeh/bro/provider/openai"
+	"github.com/festeh/bro/tools"
+)
+
+// defaultModel is used when neither agent.Model nor appConfig.Defaults.Active
+// names a model, for backends where it's
+// meaningful to default at all (openrouter only - the others require a
+// model name, since there's no one-size-fits-all local or hosted default).
+const defaultModel = "z-ai/glm-4.5"
+
+// NewProvider builds the ChatCompletionProvider agent asked for via its
+// Provider/Model fields. An unset Provider falls back to
+// appConfig.Defaults.Provider, then openrouter; an unset Model falls back
+// to appConfig.Defaults.Active (resolved through config.Config.ResolveModel).
+// This is the one place App decides which backend vendor is live;
+// everywhere else talks to agent's choice only through the
+// provider.ChatCompletionProvider interface.
+//
+// appConfig's Tools (global) and, for openrouter, the active model's
+// config.ModelEntry (Temperature/MaxTokens/Tools, matched against the
+// resolved model id - see config.Config.ResolveModel) further narrow the
+// toolbox and override the request defaults. Callers with no loaded
+// config.yaml (cmd's one-shot completion path) can pass a zero
+// config.Config{}; every field it drives falls back to its existing
+// default unchanged.
+func NewProvider(appConfig config.Config, agent agents.Agent, env *environment.Environment) (provider.ChatCompletionProvider, error) {
+	registry := applyToolPolicy(agents.BuildToolRegistry(agent, appConfig.Output), appConfig.Tools)
+
+	backend := agent.Provider
+	if backend == "" {
+		backend = appConfig.Defaults.Provider
+	}
+	if agent.Model == "" && appConfig.Defaults.Active != "" {
+		agent.Model = appConfig.Defaults.Active
+		if entry, ok := appConfig.ResolveModel(agent.Model); ok {
+			agent.Model = entry.Model
+		}
+	}
+
+	switch backend {
+	case "", "openrouter":
+		model := agent.Model
+		if model == "" {
+			model = defaultModel
+		}
+
+		temperature := appConfig.Defaults.Temperature
+		maxTokens := appConfig.Defaults.MaxTokens
+		if entry, ok := appConfig.ResolveModel(model); ok {
+			registry = applyToolPolicy(registry, entry.Tools)
+			if entry.Temperature != 0 {
+				temperature = entry.Temperature
+			}
+			if entry.MaxTokens != 0 {
+				maxTokens = entry.MaxTokens
+			}
+		}
+
+		return openrouter.NewClient(env, &openrouter.Config{
+			Model:        model,
+			ToolRegistry: registry,
+			Temperature:  temperature,
+			MaxTokens:    maxTokens,
+		})
+	case "ollama":
+		if agent.Model == "" {
+			return nil, fmt.Errorf("agent %q: ollama provider requires a model", agent.Name)
+		}
+		return ollama.NewClient(&ollama.Config{
+			Config: provider.Config{Model: agent.Model, ToolRegistry: registry},
+		})
+	case "anthropic":
+		if agent.Model == "" {
+			return nil, fmt.Errorf("agent %q: anthropic provider requires a model", agent.Name)
+		}
+		return anthropic.NewClient(&anthropic.Config{
+			Config: provider.Config{Model: agent.Model, ToolRegistry: registry},
+		})
+	case "openai":
+		if agent.Model == "" {
+			return nil, fmt.Errorf("agent %q: openai provider requires a model", agent.Name)
+		}
+		return openai.NewClient(&openai.Config{
+			Config: provider.Config{Model: agent.Model, ToolRegistry: registry},
+		})
+	case "gemini":
+		if agent.Model == "" {
+			return nil, fmt.Errorf("agent %q: gemini provider requires a model", agent.Name)
+		}
+		return gemini.NewClient(&gemini.Config{
+			Config: provider.Config{Model: agent.Model, ToolRegistry: registry},
+		})
+	default:
+		return nil, fmt.Errorf("agent %q: unknown provider %q", agent.Name, backend)
+	}
+}
+
+// applyToolPolicy narrows registry down to policy.Allow (if set, the only
+// tools kept) or away from policy.Deny, returning registry unchanged when
+// policy sets neither.
+func applyToolPolicy(registry *tools.Registry, policy config.ToolPolicy) *tools.Registry {
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		return registry
+	}
+
+	allow := make(map[string]bool, len(policy.Allow))
+	for _, name := range policy.Allow {
+		allow[name] = true
+	}
+	deny := make(map[string]bool, len(policy.Deny))
+	for _, name := range policy.Deny {
+		deny[name] = true
+	}
+
+	filtered := tools.NewRegistry()
+	for _, tool := range registry.GetAll() {
+		if len(allow) > 0 && !allow[tool.Name()] {
+			continue
+		}
+		if deny[tool.Name()] {
+			continue
+		}
+		filtered.Register(tool)
+	}
+	return filtered
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/festeh/bro/openrouter"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// renderCacheEntry memoizes one message's wrapped, syntax-highlighted
+// render at a given width (see renderMessage), so View's keystroke-driven
+// re-renders don't re-wrap - and re-run glamour's markdown parser over -
+// every message in the transcript, only the ones that actually changed
+// since the last draw.
+type renderCacheEntry struct {
+	width   int
+	source  string
+	wrapped []string
+}
+
+// renderMessage renders msg's content to word- and rune-wrapped lines at
+// chatWidth, using a's per-message-index cache and recomputing only when
+// msg's own Render() output or chatWidth has changed since the entry was
+// cached. index is the message's position in a.messages; callers that
+// render something not backed by a stable index (e.g. the in-flight
+// a.currentResponse) should call wrapContent directly instead.
+func (a *App) renderMessage(index int, msg openrouter.Renderable, chatWidth int) []string {
+	rendered := msg.Render()
+
+	if entry, ok := a.renderCache[index]; ok && entry.width == chatWidth && entry.source == rendered {
+		return entry.wrapped
+	}
+
+	wrapped := wrapContent(highlightMarkdown(rendered, msg), chatWidth)
+	a.renderCache[index] = renderCacheEntry{width: chatWidth, source: rendered, wrapped: wrapped}
+	return wrapped
+}
+
+// wrapContent word- and rune-wraps content to width with
+// muesli/reflow/wordwrap, replacing the hard byte-slicing View used to do
+// (which broke on multibyte runes and ANSI escape sequences). wordwrap
+// won't break a run with no spaces longer than width - e.g. a long path or
+// URL - so those are hard-wrapped afterwards by printable rune width
+// instead of falling back to byte slicing.
+func wrapContent(content string, width int) []string {
+	if width <= 0 {
+		width = 80
+	}
+
+	var out []string
+	for _, line := range strings.Split(wordwrap.String(content, width), "\n") {
+		for ansi.PrintableRuneWidth(line) > width {
+			cut := cutToWidth(line, width)
+			out = append(out, line[:cut])
+			line = line[cut:]
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// cutToWidth returns the byte offset within line where its printable width
+// first exceeds width, walking rune-by-rune so a multibyte rune is never
+// split across the cut.
+func cutToWidth(line string, width int) int {
+	cut := 0
+	for i := range line {
+		if ansi.PrintableRuneWidth(line[:i]) > width {
+			return cut
+		}
+		cut = i
+	}
+	if ansi.PrintableRuneWidth(line) > width {
+		return cut
+	}
+	return len(line)
+}
+
+// highlightMarkdown runs an assistant message's rendered text through
+// glamour so fenced code blocks get syntax-highlighted in the TUI. User,
+// system, and tool messages aren't modeled as markdown, so they're left as
+// plain text; a glamour failure (e.g. malformed fences) falls back to the
+// unhighlighted render rather than dropping the message.
+func highlightMarkdown(rendered string, msg openrouter.Renderable) string {
+	if msg.IsUser() {
+		return rendered
+	}
+	if chat, ok := msg.(*openrouter.ChatMessage); !ok || chat.Role != "assistant" {
+		return rendered
+	}
+
+	out, err := glamour.Render(rendered, "dark")
+	if err != nil {
+		return rendered
+	}
+	return strings.TrimRight(out, "\n")
+}
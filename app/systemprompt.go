@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/festeh/bro/agents"
+)
+
+// GenerateSystemPrompt returns the dynamic environment preamble (current
+// time, OS, working directory) shared by every agent's system prompt.
+func GenerateSystemPrompt() string {
+	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	currentDir, err := os.Getwd()
+	if err != nil {
+		currentDir = "unknown"
+	}
+
+	osInfo := fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
+
+	return fmt.Sprintf(`Current time: %s
+OS: %s
+Working directory: %s`, currentTime, osInfo, currentDir)
+}
+
+// buildSystemPrompt combines the dynamic environment preamble with the
+// selected agent's own system prompt.
+func buildSystemPrompt(agent agents.Agent) string {
+	return fmt.Sprintf("%s\n%s", GenerateSystemPrompt(), agent.SystemPrompt)
+}
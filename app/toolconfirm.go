@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/openrouter"
+	"github.com/festeh/bro/tools"
+)
+
+// policyFor resolves the effective policy for tool: a session "always
+// allow" override takes precedence over the agent's own policy.
+func (a *App) policyFor(tool string) agents.ToolPolicy {
+	if policy, ok := a.toolPolicies[tool]; ok {
+		return policy
+	}
+	return a.agent.PolicyFor(tool)
+}
+
+// toolProgressMsg carries an incremental tools.ProgressUpdate from a
+// running tool call back into Update, so the view can render elapsed
+// output instead of looking hung while a slow tool (a big grep, a long
+// watcher timeout) is still running.
+type toolProgressMsg tools.ProgressUpdate
+
+// toolResultMsg carries the outcome of a tool call started by
+// runToolCall back into Update once it finishes, successfully or not.
+type toolResultMsg struct {
+	toolCall openrouter.ToolCall
+	result   string
+	err      error
+}
+
+// runToolCall records toolCall as made and starts it running against the
+// client's tool registry in the background, so a slow tool doesn't block
+// the whole TUI the way a direct call would. Progress is reported back
+// through a.eventChan as toolProgressMsg while it runs, mirroring how
+// streamCompletions reports LLM output; the eventual result arrives as a
+// single toolResultMsg. a.toolCancel lets Update cancel it from a ctrl+c
+// keypress while a.mode == "tool-running".
+func (a *App) runToolCall(toolCall openrouter.ToolCall) {
+	log.Info("Executing tool call", "name", toolCall.Function.Name)
+	a.messages = append(a.messages, &openrouter.ToolCallMessage{ToolCall: toolCall})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.toolCancel = cancel
+	a.runningTool = &toolCall
+	a.toolProgress = tools.ProgressUpdate{}
+	a.mode = "tool-running"
+
+	registry := a.client.GetToolRegistry()
+	eventChan := a.eventChan
+	session := a.config.Session
+	go func() {
+		progress := tools.ReporterFunc(func(update tools.ProgressUpdate) {
+			eventChan <- toolProgressMsg(update)
+		})
+		result, err := tools.ExecuteTool(ctx, registry, toolCall.Function.Name, []byte(toolCall.Function.Arguments), progress)
+		if logErr := session.LogToolCall(toolCall.Function.Name, toolCall.Function.Arguments, result); logErr != nil {
+			log.Error("Failed to log tool call to session", "error", logErr)
+		}
+		eventChan <- toolResultMsg{toolCall: toolCall, result: result, err: err}
+	}()
+}
+
+// denyToolCall records a refused tool call as an error tool response, so
+// the model sees that its call didn't run instead of waiting on it forever.
+func (a *App) denyToolCall(toolCall openrouter.ToolCall) {
+	log.Info("Denied tool call", "name", toolCall.Function.Name)
+	a.messages = append(a.messages, &openrouter.ToolCallMessage{ToolCall: toolCall})
+	a.messages = append(a.messages, &openrouter.ToolResponseMessage{
+		ToolCallID: toolCall.ID,
+		ToolName:   toolCall.Function.Name,
+		Error:      fmt.Errorf("denied by user"),
+	})
+}
+
+// advanceToolQueue skips or starts the front of a.toolCallQueue according
+// to policy, stopping the moment it reaches one that requires a prompt or
+// is now running in the background (see runToolCall). Denied calls are
+// resolved immediately and don't stop the loop. Returns true once the
+// whole queue has been drained with nothing left running.
+func (a *App) advanceToolQueue() bool {
+	for len(a.toolCallQueue) > 0 {
+		toolCall := a.toolCallQueue[0]
+		switch a.policyFor(toolCall.Function.Name) {
+		case agents.PolicyDeny:
+			a.denyToolCall(toolCall)
+			a.toolCallQueue = a.toolCallQueue[1:]
+		case agents.PolicyAutoApprove:
+			a.runToolCall(toolCall)
+			return false
+		default: // PolicyPrompt
+			a.awaitingTool = &toolCall
+			a.mode = "confirm"
+			a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf(
+				"Tool call requires confirmation: %s(%s)\n  [y] approve once   [n] deny   [a] always allow %s this session",
+				toolCall.Function.Name, toolCall.Function.Arguments, toolCall.Function.Name,
+			)))
+			return false
+		}
+	}
+	return true
+}
+
+// resolveAwaitingTool handles a y/n/a keypress while a.mode == "confirm".
+// Approving starts the call running in the background (see runToolCall)
+// and leaves the queue draining until its toolResultMsg arrives; denying
+// is immediate, so it resumes draining the queue itself.
+func (a *App) resolveAwaitingTool(key string) {
+	if a.awaitingTool == nil {
+		a.mode = "chat"
+		return
+	}
+
+	toolCall := *a.awaitingTool
+	switch key {
+	case "y":
+		a.awaitingTool = nil
+		a.runToolCall(toolCall)
+	case "a":
+		if a.toolPolicies == nil {
+			a.toolPolicies = make(map[string]agents.ToolPolicy)
+		}
+		a.toolPolicies[toolCall.Function.Name] = agents.PolicyAutoApprove
+		a.awaitingTool = nil
+		a.runToolCall(toolCall)
+	case "n":
+		a.denyToolCall(toolCall)
+		a.toolCallQueue = a.toolCallQueue[1:]
+		a.awaitingTool = nil
+		a.mode = "chat"
+		if a.advanceToolQueue() {
+			a.finishToolExecution()
+		}
+	default:
+		return
+	}
+}
+
+// handleToolResult applies a finished tool call's outcome (msg, from
+// runToolCall) to the transcript, then resumes draining the queue - the
+// same continuation resolveAwaitingTool's deny branch and advanceToolQueue
+// itself already use.
+func (a *App) handleToolResult(msg toolResultMsg) {
+	a.runningTool = nil
+	a.toolCancel = nil
+	a.toolProgress = tools.ProgressUpdate{}
+	a.mode = "chat"
+
+	a.messages = append(a.messages, &openrouter.ToolResponseMessage{
+		ToolCallID: msg.toolCall.ID,
+		ToolName:   msg.toolCall.Function.Name,
+		Result:     msg.result,
+		Error:      msg.err,
+	})
+	a.toolCallQueue = a.toolCallQueue[1:]
+
+	if a.advanceToolQueue() {
+		a.finishToolExecution()
+	}
+}
+
+// finishToolExecution feeds the tool results back to the model once the
+// whole tool-call queue from this turn has been resolved (executed or
+// denied), closing the ReAct-style loop: the model sees what its tools
+// returned and can decide whether to call more of them. maxToolIterations
+// (App.client.GetMaxToolIterations) bounds how many such round trips a
+// single user turn may take, so a model that keeps calling tools forever
+// can't run away.
+func (a *App) finishToolExecution() {
+	if !a.toolBatchPending {
+		log.Info("No tool calls to execute")
+		a.toolBatchPending = false
+		return
+	}
+	a.toolBatchPending = false
+
+	a.toolIteration++
+	maxIterations := a.client.GetMaxToolIterations()
+	if a.toolIteration > maxIterations {
+		log.Info("Reached max tool iterations, stopping loop", "limit", maxIterations)
+		a.messages = append(a.messages, openrouter.NewCommandResponseMessage(fmt.Sprintf(
+			"Stopped after %d tool iterations (limit reached). Ask me to continue if more steps are needed.", maxIterations,
+		)))
+		return
+	}
+
+	log.Info("Send tool call results", "iteration", a.toolIteration, "limit", maxIterations)
+	a.messages = append(a.messages, openrouter.NewCommandResponseMessage(
+		fmt.Sprintf("— tool iteration %d/%d —", a.toolIteration, maxIterations),
+	))
+	go a.streamCompletions()()
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/app"
+	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/environment"
+	"github.com/festeh/bro/openrouter"
+	"github.com/festeh/bro/store"
+)
+
+// runCompletion sends history (root-to-leaf, oldest first) to agent's
+// configured provider and blocks until the reply is complete, returning its
+// text. It's the non-interactive counterpart to App.streamCompletions: the
+// new/reply subcommands don't run a TUI event loop, so there's no one to
+// drive tool confirmations - a reply that requests a tool call is reported
+// as an error instead of executed.
+func runCompletion(agent agents.Agent, history []store.Message) (string, error) {
+	env, err := environment.NewEnvironment()
+	if err != nil {
+		return "", fmt.Errorf("initializing environment: %w", err)
+	}
+
+	// runCompletion doesn't load ~/.bro/config.yaml (no TUI session to
+	// amortize the history/session/stats init InitializeBroDirectory also
+	// does) - a zero config.Config leaves NewProvider's defaults exactly
+	// as they were before config.yaml existed.
+	client, err := app.NewProvider(config.Config{}, agent, env)
+	if err != nil {
+		return "", fmt.Errorf("initializing chat completion provider: %w", err)
+	}
+
+	rendered := []openrouter.Renderable{
+		openrouter.NewSystemMessage(app.GenerateSystemPrompt() + "\n" + agent.SystemPrompt),
+	}
+	for _, msg := range history {
+		switch msg.Role {
+		case "user":
+			rendered = append(rendered, openrouter.NewUserMessage(msg.Content))
+		case "assistant":
+			rendered = append(rendered, openrouter.NewAssistantMessage(msg.Content, client.GetModel()))
+		}
+	}
+	messages := openrouter.ChatMessagesToOpenRouter(rendered)
+
+	var content string
+	done := make(chan error, 1)
+
+	err = client.SendMessages(messages, func(event openrouter.StreamEvent) {
+		switch event.Type {
+		case openrouter.StreamEventChunk:
+			content += event.Content
+		case openrouter.StreamEventToolCall:
+			select {
+			case done <- fmt.Errorf("agent %q requested a tool call, which isn't supported outside the TUI", agent.Name):
+			default:
+			}
+		case openrouter.StreamEventError:
+			select {
+			case done <- event.Error:
+			default:
+			}
+		case openrouter.StreamEventDone:
+			select {
+			case done <- nil:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := <-done; err != nil {
+		return "", err
+	}
+	return content, nil
+}
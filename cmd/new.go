@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/festeh/bro/store"
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <prompt>",
+	Short: "Start a new conversation and get a reply",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prompt := strings.Join(args, " ")
+
+		s, err := openStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+
+		conv, root, err := s.NewConversation(prompt)
+		if err != nil {
+			return fmt.Errorf("creating conversation: %w", err)
+		}
+
+		agent := selectAgent(agentName)
+		reply, err := runCompletion(agent, []store.Message{*root})
+		if err != nil {
+			return fmt.Errorf("getting reply: %w", err)
+		}
+
+		if _, err := s.Reply(conv.ID, "assistant", reply); err != nil {
+			return fmt.Errorf("saving reply: %w", err)
+		}
+
+		fmt.Printf("conversation %s\n\n%s\n", conv.ID, reply)
+		return nil
+	},
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var replyCmd = &cobra.Command{
+	Use:   "reply <conversation-id> <message>",
+	Short: "Continue an existing conversation and get a reply",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conversationID := args[0]
+		message := strings.Join(args[1:], " ")
+
+		s, err := openStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+
+		if _, err := s.Reply(conversationID, "user", message); err != nil {
+			return fmt.Errorf("saving message: %w", err)
+		}
+
+		history, err := s.Branch(conversationID)
+		if err != nil {
+			return fmt.Errorf("loading conversation: %w", err)
+		}
+
+		agent := selectAgent(agentName)
+		reply, err := runCompletion(agent, history)
+		if err != nil {
+			return fmt.Errorf("getting reply: %w", err)
+		}
+
+		if _, err := s.Reply(conversationID, "assistant", reply); err != nil {
+			return fmt.Errorf("saving reply: %w", err)
+		}
+
+		fmt.Println(reply)
+		return nil
+	},
+}
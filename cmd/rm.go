@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <conversation-id>",
+	Short: "Delete a conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+
+		if err := s.Delete(args[0]); err != nil {
+			return fmt.Errorf("deleting conversation: %w", err)
+		}
+
+		fmt.Printf("deleted %s\n", args[0])
+		return nil
+	},
+}
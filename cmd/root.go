@@ -0,0 +1,183 @@
+// Package cmd wires bro's command-line surface: the interactive TUI by
+// default (unchanged from before this package existed), plus
+// conversation-management subcommands (new, reply, view, rm, ls) backed
+// by store.Store. main.go just calls cmd.Execute().
+package cmd
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/app"
+	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentName      string
+	toolPolicyFlag string
+	resumeID       string
+	resumeSession  bool
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "bro",
+	Short: "A terminal AI coding assistant",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI()
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&agentName, "agent", "a", "", "agent to use, see ~/.config/bro/agents.yaml (default \""+agents.DefaultAgentName+"\")")
+	rootCmd.Flags().StringVar(&toolPolicyFlag, "tool-policy", "", "override tool confirmation policy, e.g. \"bash=auto-approve,fileedit=deny\"")
+	rootCmd.Flags().StringVar(&resumeID, "resume", "", "resume an existing conversation by id instead of starting a fresh one")
+	rootCmd.Flags().BoolVar(&resumeSession, "resume-session", false, "resume the most recent session log (~/.bro/DDMon_YYYY/HH_MM.jsonl) instead of starting fresh; independent of --resume, which resumes a stored conversation by id")
+
+	rootCmd.AddCommand(newCmd, replyCmd, viewCmd, rmCmd, lsCmd)
+}
+
+// Execute runs the root command, exiting the process on error the same
+// way the old flag-based main() did.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		log.Error("bro exited with an error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runTUI is the root command's default action: the same interactive app
+// bro has always launched when given no subcommand.
+func runTUI() error {
+	logFile, err := os.Create("log.txt")
+	if err != nil {
+		log.Fatal("Failed to create log file", "error", err)
+	}
+	defer logFile.Close()
+
+	log.SetOutput(logFile)
+	log.SetLevel(log.InfoLevel)
+	log.Info("Application starting")
+
+	appConfig, err := config.InitializeBroDirectory()
+	if err != nil {
+		log.Error("Failed to initialize ~/.bro directory", "error", err)
+	}
+
+	selectedAgent := selectAgent(agentName)
+
+	toolPolicyOverrides, err := agents.ParsePolicyOverrides(toolPolicyFlag)
+	if err != nil {
+		log.Error("Failed to parse -tool-policy, ignoring", "error", err)
+	}
+	toolPolicyOverrides = mergeToolPolicies(appConfigToolConfirmations(appConfig), toolPolicyOverrides)
+
+	var bro app.App
+	if resumeID != "" {
+		bro, err = app.NewAppWithResume(*appConfig, selectedAgent, toolPolicyOverrides, resumeID)
+		if err != nil {
+			log.Error("Failed to resume conversation, starting fresh", "conversation", resumeID, "error", err)
+			bro = app.NewAppWithPolicies(*appConfig, selectedAgent, toolPolicyOverrides)
+		}
+	} else {
+		bro = app.NewAppWithPolicies(*appConfig, selectedAgent, toolPolicyOverrides)
+	}
+
+	if resumeSession {
+		path, err := config.MostRecentSessionFile()
+		if err != nil {
+			log.Error("Failed to find a session to resume, starting fresh", "error", err)
+		} else if path == "" {
+			log.Info("No prior sessions found, starting fresh")
+		} else if n, err := bro.ResumeFromSession(path); err != nil {
+			log.Error("Failed to resume session, starting fresh", "session", path, "error", err)
+		} else {
+			log.Info("Resumed session", "session", path, "messages", n)
+		}
+	}
+
+	p := tea.NewProgram(bro)
+	if _, err := p.Run(); err != nil {
+		log.Error("Failed to run program", "error", err)
+	}
+
+	if appConfig != nil {
+		if err := appConfig.Session.Close(); err != nil {
+			log.Error("Failed to close session file", "error", err)
+		}
+	}
+
+	log.Info("Application exiting")
+	return nil
+}
+
+// appConfigToolConfirmations returns appConfig's config.yaml "confirm:"
+// allowlist, or nil if appConfig failed to load (InitializeBroDirectory
+// already logged the error).
+func appConfigToolConfirmations(appConfig *config.Config) map[string]agents.ToolPolicy {
+	if appConfig == nil {
+		return nil
+	}
+	return appConfig.ToolConfirmations
+}
+
+// mergeToolPolicies layers overrides (the --tool-policy flag) on top of
+// base (config.yaml's "confirm:" allowlist), so a tool named in both wins
+// with the flag's value - the flag is the more specific, session-only
+// request.
+func mergeToolPolicies(base, overrides map[string]agents.ToolPolicy) map[string]agents.ToolPolicy {
+	if len(base) == 0 {
+		return overrides
+	}
+
+	merged := make(map[string]agents.ToolPolicy, len(base)+len(overrides))
+	for tool, policy := range base {
+		merged[tool] = policy
+	}
+	for tool, policy := range overrides {
+		merged[tool] = policy
+	}
+	return merged
+}
+
+// selectAgent loads the agents config (falling back to the built-in agents
+// when it's missing) and resolves name to an Agent, defaulting to
+// agents.DefaultAgentName when name is empty or unknown.
+func selectAgent(name string) agents.Agent {
+	configPath, err := agents.DefaultConfigPath()
+	if err != nil {
+		log.Error("Failed to resolve agents config path", "error", err)
+		return agents.NewDefaultRegistry().Default()
+	}
+
+	registry, err := agents.LoadRegistry(configPath)
+	if err != nil {
+		log.Error("Failed to load agents config, falling back to built-in agents", "error", err)
+		registry = agents.NewDefaultRegistry()
+	}
+
+	if name == "" {
+		return registry.Default()
+	}
+
+	agent, ok := registry.Get(name)
+	if !ok {
+		log.Error("Unknown agent, falling back to default", "agent", name)
+		return registry.Default()
+	}
+
+	return agent
+}
+
+// openStore opens the conversation store at its default path
+// (~/.bro/bro.db), used by every conversation subcommand.
+func openStore() (*store.Store, error) {
+	path, err := store.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(path)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/festeh/bro/config"
+	"github.com/festeh/bro/environment"
+	"github.com/festeh/bro/support"
+	"github.com/spf13/cobra"
+)
+
+var (
+	supportDumpStdout bool
+	supportDumpOut    string
+)
+
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Diagnostic commands for reporting bugs",
+}
+
+var supportDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Collect a redacted diagnostic bundle to attach to a GitHub issue",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if supportDumpStdout && supportDumpOut != "" {
+			return fmt.Errorf("--stdout and --out are mutually exclusive")
+		}
+
+		appConfig, err := config.InitializeBroDirectory()
+		if err != nil {
+			return fmt.Errorf("loading ~/.bro: %w", err)
+		}
+		defer appConfig.Session.Close()
+
+		var apiKey string
+		if env, err := environment.NewEnvironment(); err == nil {
+			apiKey = env.APIKey
+		}
+		// Every credential a backend this series added might have read -
+		// support.Redact needs all of them, not just OpenRouter's, or a
+		// dump taken while running against anthropic/gemini/openai leaks
+		// that backend's key.
+		apiKeys := []string{apiKey, os.Getenv("ANTHROPIC_API_KEY"), os.Getenv("GEMINI_API_KEY"), os.Getenv("OPENAI_API_KEY")}
+
+		agent := selectAgent(agentName)
+
+		if supportDumpStdout {
+			return support.Dump(os.Stdout, appConfig, agent, apiKeys...)
+		}
+
+		outPath := supportDumpOut
+		if outPath == "" {
+			outPath = "bro-support.tar.gz"
+		}
+
+		f, err := os.Create(outPath)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", outPath, err)
+		}
+		defer f.Close()
+
+		if err := support.Dump(f, appConfig, agent, apiKeys...); err != nil {
+			return fmt.Errorf("writing diagnostic bundle: %w", err)
+		}
+
+		fmt.Printf("wrote diagnostic bundle to %s\n", outPath)
+		return nil
+	},
+}
+
+func init() {
+	supportDumpCmd.Flags().BoolVar(&supportDumpStdout, "stdout", false, "stream the diagnostic tarball to stdout instead of writing a file")
+	supportDumpCmd.Flags().StringVar(&supportDumpOut, "out", "", "write the diagnostic tarball to this path (default: bro-support.tar.gz in the current directory)")
+	supportCmd.AddCommand(supportDumpCmd)
+	rootCmd.AddCommand(supportCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view <conversation-id>",
+	Short: "Print a conversation's active branch",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openStore()
+		if err != nil {
+			return fmt.Errorf("opening store: %w", err)
+		}
+		defer s.Close()
+
+		messages, err := s.Branch(args[0])
+		if err != nil {
+			return fmt.Errorf("loading conversation: %w", err)
+		}
+
+		for _, msg := range messages {
+			fmt.Printf("[%s] %s\n\n", msg.Role, msg.Content)
+		}
+		return nil
+	},
+}
@@ -1,20 +1,31 @@
 package config
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/tools"
 	"slices"
 )
 
 type Config struct {
 	AvailableModels []string
-	History         History
-	Session         Session
-	Stats           *Stats
+	Models          []ModelEntry
+	Tools           ToolPolicy
+	// ToolConfirmations is config.yaml's "confirm:" allowlist, e.g.
+	// {"grep": agents.PolicyAutoApprove}: a per-tool confirmation policy
+	// that applies session-wide and takes precedence over an agent's own
+	// ToolPolicies; a --tool-policy entry for the same tool takes
+	// precedence over it in turn. Unset tools fall back to
+	// agents.DefaultToolPolicy exactly as before this existed.
+	ToolConfirmations map[string]agents.ToolPolicy
+	Defaults          Defaults
+	Output            tools.OutputPolicy
+	History           History
+	Session           Session
+	Stats             *Stats
 }
 
 func InitializeBroDirectory() (*Config, error) {
@@ -30,19 +41,27 @@ func InitializeBroDirectory() (*Config, error) {
 		return nil, err
 	}
 
-	modelsFile := filepath.Join(broDir, "models.txt")
+	configFile := filepath.Join(broDir, ModelsConfigFile)
 
-	// Check if models.txt exists, if not create it by calling UpdateModels
-	if _, err := os.Stat(modelsFile); os.IsNotExist(err) {
-		log.Info("models.txt not found, creating it...")
-		if err := UpdateModels(); err != nil {
+	// Check if config.yaml exists; if not, migrate an existing models.txt
+	// into it, or seed it with the default model list if there's nothing
+	// to migrate.
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		migrated, err := migrateLegacyModels(broDir)
+		if err != nil {
 			return nil, err
 		}
+		if !migrated {
+			log.Info("config.yaml not found, creating it...")
+			if err := UpdateModels(); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Initialize config and load available models
 	config := &Config{}
-	if err := loadAvailableModels(config); err != nil {
+	if err := loadModelsConfig(config); err != nil {
 		return nil, err
 	}
 
@@ -52,10 +71,10 @@ func InitializeBroDirectory() (*Config, error) {
 		log.Error("Failed to initialize history", "error", err)
 		// Create empty history as fallback
 		config.History = History{
-			commands: make([]string, HISTORY_SIZE),
-			head:     0,
-			size:     0,
-			dirPath:  filepath.Join(homeDir, ".bro"),
+			records: make([]HistoryRecord, HISTORY_SIZE),
+			head:    0,
+			size:    0,
+			dirPath: filepath.Join(homeDir, ".bro"),
 		}
 	} else {
 		config.History = *history
@@ -83,69 +102,15 @@ func InitializeBroDirectory() (*Config, error) {
 		config.Stats = stats
 	}
 
-	return config, nil
-}
-
-func UpdateModels() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
+	if config.Stats != nil {
+		config.Stats.SetHistory(&config.History)
 	}
 
-	modelsFile := filepath.Join(homeDir, ".bro", "models.txt")
-
-	// Create a basic models.txt file with some default models
-	models := []string{
-		"anthropic/claude-sonnet-4",
-		"x-ai/grok-4",
-		"qwen/qwen3-coder",
-		"openai/gpt-4o",
-		"meta-llama/llama-3.1-405b-instruct",
-		"google/gemini-2.0-flash-exp",
-	}
-	modelsContent := strings.Join(models, "\n")
-
-	if err := os.WriteFile(modelsFile, []byte(modelsContent), 0644); err != nil {
-		return err
-	}
-
-	log.Info("Updated models.txt successfully")
-	return nil
-}
-
-func (c *Config) UpdateAvailableModels() error {
-	return loadAvailableModels(c)
+	return config, nil
 }
 
-func loadAvailableModels(config *Config) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	modelsFile := filepath.Join(homeDir, ".bro", "models.txt")
-	file, err := os.Open(modelsFile)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	var models []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			models = append(models, line)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return err
-	}
-
-	config.AvailableModels = models
-	return nil
-}
+// UpdateModels, UpdateAvailableModels, and loadModelsConfig live in
+// modelsconfig.go alongside the rest of the config.yaml read/write logic.
 
 func (c *Config) IsValidModel(modelName string) bool {
 	if c == nil {
@@ -7,36 +7,63 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
+	brofs "github.com/festeh/bro/fs"
 )
 
 const (
 	HISTORY_SIZE = 100
-	HISTORY_FILE = "history.txt"
-	INDEX_FILE   = "history_index.txt"
+	HISTORY_FILE = "history.rec"
+
+	// Legacy plain-text format, read once to migrate existing installs.
+	LEGACY_HISTORY_FILE = "history.txt"
+	LEGACY_INDEX_FILE   = "history_index.txt"
 )
 
+// HistoryRecord is one entry in the recfile-style history log: a command
+// plus the timing/session/cost context around it. Time is always set;
+// the rest are filled in later through UpdateLastRecord, whose one caller,
+// Stats.AddUsage, passes only Tokens and Cost - Session and Duration are
+// left empty.
+type HistoryRecord struct {
+	Time     time.Time
+	Command  string
+	Session  string
+	Tokens   int
+	Cost     float64
+	Duration time.Duration
+}
+
 type History struct {
-	commands []string
-	head     int // Index where next command will be written
-	size     int // Current number of commands (up to HISTORY_SIZE)
-	dirPath  string
+	records []HistoryRecord
+	head    int // Index where next record will be written
+	size    int // Current number of records (up to HISTORY_SIZE)
+	dirPath string
+	fs      brofs.FS
 }
 
+// NewHistory creates a History backed by the real filesystem, rooted at
+// ~/.bro.
 func NewHistory() (*History, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	dirPath := filepath.Join(homeDir, ".bro")
+	return NewHistoryWithFS(brofs.NewOSFs(), filepath.Join(homeDir, ".bro"))
+}
 
+// NewHistoryWithFS creates a History backed by the given filesystem, rooted
+// at dirPath. Tests can pass brofs.NewMemFs() to avoid touching disk.
+func NewHistoryWithFS(filesystem brofs.FS, dirPath string) (*History, error) {
 	h := &History{
-		commands: make([]string, HISTORY_SIZE),
-		head:     0,
-		size:     0,
-		dirPath:  dirPath,
+		records: make([]HistoryRecord, HISTORY_SIZE),
+		head:    0,
+		size:    0,
+		dirPath: dirPath,
+		fs:      filesystem,
 	}
 
 	if err := h.load(); err != nil {
@@ -55,12 +82,15 @@ func (h *History) AddCommand(command string) error {
 	// Don't add duplicate consecutive commands
 	if h.size > 0 {
 		lastIndex := (h.head - 1 + HISTORY_SIZE) % HISTORY_SIZE
-		if h.commands[lastIndex] == command {
+		if h.records[lastIndex].Command == command {
 			return nil
 		}
 	}
 
-	h.commands[h.head] = command
+	h.records[h.head] = HistoryRecord{
+		Time:    time.Now(),
+		Command: command,
+	}
 	h.head = (h.head + 1) % HISTORY_SIZE
 
 	if h.size < HISTORY_SIZE {
@@ -70,20 +100,56 @@ func (h *History) AddCommand(command string) error {
 	return h.save()
 }
 
+// UpdateLastRecord fills in the per-request cost/token/duration fields on
+// the most recently added record, so a command's history entry carries its
+// own spend instead of requiring a join against config.Stats.
+func (h *History) UpdateLastRecord(session string, tokens int, cost float64, duration time.Duration) error {
+	if h.size == 0 {
+		return nil
+	}
+
+	lastIndex := (h.head - 1 + HISTORY_SIZE) % HISTORY_SIZE
+	record := &h.records[lastIndex]
+	if session != "" {
+		record.Session = session
+	}
+	record.Tokens += tokens
+	record.Cost += cost
+	if duration > 0 {
+		record.Duration = duration
+	}
+
+	return h.save()
+}
+
 func (h *History) GetCommands() []string {
+	records := h.GetRecords()
+	if records == nil {
+		return nil
+	}
+
+	commands := make([]string, len(records))
+	for i, r := range records {
+		commands[i] = r.Command
+	}
+	return commands
+}
+
+// GetRecords returns the full history in chronological order (oldest first).
+func (h *History) GetRecords() []HistoryRecord {
 	if h.size == 0 {
 		return nil
 	}
 
-	result := make([]string, h.size)
+	result := make([]HistoryRecord, h.size)
 
 	if h.size < HISTORY_SIZE {
 		// Buffer not full yet
-		copy(result, h.commands[:h.size])
+		copy(result, h.records[:h.size])
 	} else {
 		// Buffer is full, need to handle wrap-around
-		copy(result, h.commands[h.head:])
-		copy(result[HISTORY_SIZE-h.head:], h.commands[:h.head])
+		copy(result, h.records[h.head:])
+		copy(result[HISTORY_SIZE-h.head:], h.records[:h.head])
 	}
 
 	return result
@@ -96,82 +162,203 @@ func (h *History) save() error {
 	}
 
 	historyPath := filepath.Join(h.dirPath, HISTORY_FILE)
-	indexPath := filepath.Join(h.dirPath, INDEX_FILE)
 
-	// Save commands array
-	file, err := os.Create(historyPath)
+	file, err := h.fs.Create(historyPath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	for i, command := range h.commands {
-		if i < h.size || h.size == HISTORY_SIZE {
-			if _, err := file.WriteString(command + "\n"); err != nil {
-				return err
-			}
-		}
-	}
+	var buf strings.Builder
+	buf.WriteString(strconv.Itoa(h.head) + "\n")
+	buf.WriteString(strconv.Itoa(h.size) + "\n\n")
 
-	// Save metadata
-	indexFile, err := os.Create(indexPath)
-	if err != nil {
-		return err
+	for _, record := range h.GetRecords() {
+		writeRecord(&buf, record)
 	}
-	defer indexFile.Close()
 
-	metadata := strconv.Itoa(h.head) + "\n" + strconv.Itoa(h.size) + "\n"
-	_, err = indexFile.WriteString(metadata)
+	_, err = file.Write([]byte(buf.String()))
 	return err
 }
 
+func writeRecord(buf *strings.Builder, record HistoryRecord) {
+	buf.WriteString(fmt.Sprintf("Time: %s\n", record.Time.Format(time.RFC3339)))
+	buf.WriteString(fmt.Sprintf("Command: %s\n", record.Command))
+	if record.Session != "" {
+		buf.WriteString(fmt.Sprintf("Session: %s\n", record.Session))
+	}
+	if record.Tokens != 0 {
+		buf.WriteString(fmt.Sprintf("Tokens: %d\n", record.Tokens))
+	}
+	if record.Cost != 0 {
+		buf.WriteString(fmt.Sprintf("Cost: %f\n", record.Cost))
+	}
+	if record.Duration != 0 {
+		buf.WriteString(fmt.Sprintf("Duration: %s\n", record.Duration))
+	}
+	buf.WriteString("\n")
+}
+
 func (h *History) load() error {
 	historyPath := filepath.Join(h.dirPath, HISTORY_FILE)
-	indexPath := filepath.Join(h.dirPath, INDEX_FILE)
 
-	// Load metadata first
-	if _, err := os.Stat(indexPath); os.IsNotExist(err) {
-		// No history file exists yet
-		return nil
+	if _, err := h.fs.Stat(historyPath); os.IsNotExist(err) {
+		// No recfile history yet; try to migrate a legacy plain-text log.
+		return h.migrateLegacy()
 	}
 
-	indexFile, err := os.Open(indexPath)
+	file, err := h.fs.Open(historyPath)
 	if err != nil {
 		return err
 	}
-	defer indexFile.Close()
+	defer file.Close()
 
-	scanner := bufio.NewScanner(indexFile)
+	scanner := bufio.NewScanner(file)
 
+	// The head/size header is legacy metadata from when save() wrote
+	// records in their physical ring position; it's no longer consulted
+	// below because save() actually writes GetRecords()'s chronological
+	// view, so head/size are re-derived from the records themselves once
+	// they're read back.
 	if scanner.Scan() {
-		if h.head, err = strconv.Atoi(strings.TrimSpace(scanner.Text())); err != nil {
+		if _, err = strconv.Atoi(strings.TrimSpace(scanner.Text())); err != nil {
 			return err
 		}
 	}
-
 	if scanner.Scan() {
-		if h.size, err = strconv.Atoi(strings.TrimSpace(scanner.Text())); err != nil {
+		if _, err = strconv.Atoi(strings.TrimSpace(scanner.Text())); err != nil {
 			return err
 		}
 	}
+	// Blank separator line between the metadata header and the records.
+	scanner.Scan()
 
-	if err := scanner.Err(); err != nil {
+	records, err := parseRecords(scanner)
+	if err != nil {
 		return err
 	}
 
-	// Load commands
-	historyFile, err := os.Open(historyPath)
+	// records is chronological (oldest first), the same view save() wrote,
+	// and load() re-lays it into h.records starting at physical index 0 -
+	// so head/size must be re-derived from that physical layout rather
+	// than trusting the stale header, or the next AddCommand overwrites
+	// whichever slot the old head happened to point at instead of
+	// continuing from the most recent record.
+	if len(records) > HISTORY_SIZE {
+		records = records[len(records)-HISTORY_SIZE:]
+	}
+	for i, record := range records {
+		h.records[i] = record
+	}
+	h.size = len(records)
+	h.head = h.size % HISTORY_SIZE
+
+	return scanner.Err()
+}
+
+// parseRecords reads a recfile body (blank-line separated "Key: value"
+// records) from scanner until EOF.
+func parseRecords(scanner *bufio.Scanner) ([]HistoryRecord, error) {
+	var records []HistoryRecord
+	current := HistoryRecord{}
+	has := false
+
+	flush := func() {
+		if has {
+			records = append(records, current)
+		}
+		current = HistoryRecord{}
+		has = false
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		has = true
+
+		switch strings.TrimSpace(key) {
+		case "Time":
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				current.Time = t
+			}
+		case "Command":
+			current.Command = value
+		case "Session":
+			current.Session = value
+		case "Tokens":
+			if n, err := strconv.Atoi(value); err == nil {
+				current.Tokens = n
+			}
+		case "Cost":
+			if c, err := strconv.ParseFloat(value, 64); err == nil {
+				current.Cost = c
+			}
+		case "Duration":
+			if d, err := time.ParseDuration(value); err == nil {
+				current.Duration = d
+			}
+		}
+	}
+	flush()
+
+	return records, nil
+}
+
+// migrateLegacy reads the old history.txt + history_index.txt pair (a bare
+// line-per-command file plus a head/size index) and rewrites it as a
+// recfile, stamping each migrated entry with the current time since the
+// legacy format never recorded one.
+func (h *History) migrateLegacy() error {
+	indexPath := filepath.Join(h.dirPath, LEGACY_INDEX_FILE)
+	historyPath := filepath.Join(h.dirPath, LEGACY_HISTORY_FILE)
+
+	if _, err := h.fs.Stat(indexPath); os.IsNotExist(err) {
+		// Nothing to migrate; start fresh.
+		return nil
+	}
+
+	indexFile, err := h.fs.Open(indexPath)
+	if err != nil {
+		return err
+	}
+	defer indexFile.Close()
+
+	scanner := bufio.NewScanner(indexFile)
+	var legacyHead, legacySize int
+	if scanner.Scan() {
+		legacyHead, _ = strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	}
+	if scanner.Scan() {
+		legacySize, _ = strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	}
+
+	historyFile, err := h.fs.Open(historyPath)
 	if err != nil {
 		return err
 	}
 	defer historyFile.Close()
 
+	var commands []string
 	scanner = bufio.NewScanner(historyFile)
-	i := 0
-	for scanner.Scan() && i < HISTORY_SIZE {
-		h.commands[i] = strings.TrimSpace(scanner.Text())
-		i++
+	for scanner.Scan() && len(commands) < HISTORY_SIZE {
+		commands = append(commands, strings.TrimSpace(scanner.Text()))
 	}
 
-	return scanner.Err()
+	now := time.Now()
+	for i := 0; i < len(commands) && i < legacySize; i++ {
+		h.records[i] = HistoryRecord{Time: now, Command: commands[i]}
+	}
+	h.head = legacyHead
+	h.size = legacySize
+
+	return h.save()
 }
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/tools"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelsConfigFile replaced the flat LegacyModelsFile format: it carries
+// model aliases plus per-model overrides instead of one bare id per line.
+const ModelsConfigFile = "config.yaml"
+
+// LegacyModelsFile is read once to migrate an existing install into
+// ModelsConfigFile (see migrateLegacyModels); bro no longer writes it.
+const LegacyModelsFile = "models.txt"
+
+// ModelEntry is one configured model: Alias is what users type with
+// /model or a "model:" field elsewhere in config.yaml, Model is the id
+// actually sent to the provider. Temperature/MaxTokens of 0 mean "use
+// Defaults' value instead". Tools restricts which tools this model may
+// call, on top of whatever the active agent already allows.
+type ModelEntry struct {
+	Alias        string
+	Model        string
+	Temperature  float64
+	MaxTokens    int
+	SystemPrompt string
+	Tools        ToolPolicy
+}
+
+// ToolPolicy restricts a toolbox down to Allow (if non-empty, the only
+// tools permitted) or away from Deny (tools removed from an otherwise
+// full toolbox). Used both per-model and as Config.Tools' global default.
+type ToolPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Defaults holds config.yaml's top-level fallbacks.
+type Defaults struct {
+	Active      string // alias or model id selected when nothing else overrides it
+	Temperature float64
+	MaxTokens   int
+
+	// Provider is the backend an agent with no Provider of its own runs
+	// against ("openrouter", "ollama", "anthropic", "openai", "gemini");
+	// empty means openrouter, same as an agent leaving it unset (see
+	// app.NewProvider). There's no separate config-level "model:" field
+	// alongside it - Active already plays that role for every backend.
+	Provider string
+}
+
+// ActiveModel returns the ModelEntry Defaults.Active names, falling back
+// to the first configured entry. The second return is false only when no
+// models are configured at all.
+func (c *Config) ActiveModel() (ModelEntry, bool) {
+	if len(c.Models) == 0 {
+		return ModelEntry{}, false
+	}
+	if c.Defaults.Active != "" {
+		if m, ok := c.ResolveModel(c.Defaults.Active); ok {
+			return m, true
+		}
+	}
+	return c.Models[0], true
+}
+
+// ResolveModel looks up name (an alias or a raw model id) among c.Models.
+func (c *Config) ResolveModel(name string) (ModelEntry, bool) {
+	for _, m := range c.Models {
+		if m.Alias == name || m.Model == name {
+			return m, true
+		}
+	}
+	return ModelEntry{}, false
+}
+
+// defaultModelEntries is what a fresh install's config.yaml is seeded
+// with - the same ids the old models.txt default list carried, with no
+// alias of their own.
+func defaultModelEntries() []ModelEntry {
+	ids := []string{
+		"anthropic/claude-sonnet-4",
+		"x-ai/grok-4",
+		"qwen/qwen3-coder",
+		"openai/gpt-4o",
+		"meta-llama/llama-3.1-405b-instruct",
+		"google/gemini-2.0-flash-exp",
+	}
+	entries := make([]ModelEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = ModelEntry{Alias: id, Model: id}
+	}
+	return entries
+}
+
+// loadModelsConfig reads ~/.bro/config.yaml into config's Models, Tools,
+// and Defaults fields, then derives AvailableModels (each entry's Alias,
+// for /model and IsValidModel) so callers that predate aliases keep working.
+func loadModelsConfig(config *Config) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".bro", ModelsConfigFile))
+	if err != nil {
+		return err
+	}
+
+	models, toolsPolicy, defaults, output, confirmations, err := parseModelsYAML(data)
+	if err != nil {
+		return err
+	}
+
+	config.Models = models
+	config.Tools = toolsPolicy
+	config.Defaults = defaults
+	config.Output = output
+	config.ToolConfirmations = confirmations
+
+	available := make([]string, len(models))
+	for i, m := range models {
+		available[i] = m.Alias
+	}
+	config.AvailableModels = available
+	return nil
+}
+
+// migrateLegacyModels converts an existing ~/.bro/models.txt (one model id
+// per line) into ~/.bro/config.yaml, preserving each id as its own alias
+// since the old format carried no alias of its own. It returns false
+// (with a nil error) when models.txt doesn't exist, so the caller falls
+// back to writing the default config.yaml instead.
+func migrateLegacyModels(broDir string) (bool, error) {
+	file, err := os.Open(filepath.Join(broDir, LegacyModelsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	var entries []ModelEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			entries = append(entries, ModelEntry{Alias: line, Model: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return false, nil
+	}
+
+	if err := writeModelsConfig(broDir, entries, ToolPolicy{}, Defaults{}, tools.OutputPolicy{}, nil); err != nil {
+		return false, err
+	}
+	log.Info("migrated models.txt into config.yaml", "models", len(entries))
+	return true, nil
+}
+
+// yamlToolPolicy mirrors ToolPolicy with yaml tags, nested both under a
+// yamlModelEntry and at yamlModelsConfig's top level.
+type yamlToolPolicy struct {
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
+}
+
+// yamlModelEntry mirrors ModelEntry with yaml tags for config.yaml's
+// "models:" list.
+type yamlModelEntry struct {
+	Alias        string          `yaml:"alias"`
+	Model        string          `yaml:"model"`
+	Temperature  float64         `yaml:"temperature,omitempty"`
+	MaxTokens    int             `yaml:"max_tokens,omitempty"`
+	SystemPrompt string          `yaml:"system_prompt,omitempty"`
+	Tools        *yamlToolPolicy `yaml:"tools,omitempty"`
+}
+
+// yamlDefaults mirrors Defaults with yaml tags.
+type yamlDefaults struct {
+	Active      string  `yaml:"active,omitempty"`
+	Provider    string  `yaml:"provider,omitempty"`
+	Temperature float64 `yaml:"temperature,omitempty"`
+	MaxTokens   int     `yaml:"max_tokens,omitempty"`
+}
+
+// yamlOutput mirrors tools.OutputPolicy with yaml tags.
+type yamlOutput struct {
+	MaxBytes int    `yaml:"max_bytes,omitempty"`
+	MaxLines int    `yaml:"max_lines,omitempty"`
+	Strategy string `yaml:"strategy,omitempty"`
+}
+
+// yamlModelsConfig is config.yaml's on-disk shape. Pointers on the
+// optional sections keep an unconfigured section out of the marshaled
+// output entirely, matching what the old hand-rolled writer produced.
+type yamlModelsConfig struct {
+	Models   []yamlModelEntry  `yaml:"models,omitempty"`
+	Tools    *yamlToolPolicy   `yaml:"tools,omitempty"`
+	Defaults *yamlDefaults     `yaml:"defaults,omitempty"`
+	Output   *yamlOutput       `yaml:"output,omitempty"`
+	Confirm  map[string]string `yaml:"confirm,omitempty"`
+}
+
+func toYAMLToolPolicy(policy ToolPolicy) *yamlToolPolicy {
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+		return nil
+	}
+	return &yamlToolPolicy{Allow: policy.Allow, Deny: policy.Deny}
+}
+
+func fromYAMLToolPolicy(policy *yamlToolPolicy) ToolPolicy {
+	if policy == nil {
+		return ToolPolicy{}
+	}
+	return ToolPolicy{Allow: policy.Allow, Deny: policy.Deny}
+}
+
+// writeModelsConfig serializes models/tools/defaults/output/confirmations
+// to ~/.bro/config.yaml via yaml.v3, matching what loadModelsConfig reads
+// back through parseModelsYAML.
+func writeModelsConfig(broDir string, models []ModelEntry, toolsPolicy ToolPolicy, defaults Defaults, output tools.OutputPolicy, confirmations map[string]agents.ToolPolicy) error {
+	doc := yamlModelsConfig{
+		Tools: toYAMLToolPolicy(toolsPolicy),
+	}
+
+	for _, m := range models {
+		doc.Models = append(doc.Models, yamlModelEntry{
+			Alias:        m.Alias,
+			Model:        m.Model,
+			Temperature:  m.Temperature,
+			MaxTokens:    m.MaxTokens,
+			SystemPrompt: m.SystemPrompt,
+			Tools:        toYAMLToolPolicy(m.Tools),
+		})
+	}
+
+	if defaults.Active != "" || defaults.Temperature != 0 || defaults.MaxTokens != 0 || defaults.Provider != "" {
+		doc.Defaults = &yamlDefaults{
+			Active:      defaults.Active,
+			Provider:    defaults.Provider,
+			Temperature: defaults.Temperature,
+			MaxTokens:   defaults.MaxTokens,
+		}
+	}
+
+	if output.MaxBytes != 0 || output.MaxLines != 0 || output.Strategy != "" {
+		doc.Output = &yamlOutput{
+			MaxBytes: output.MaxBytes,
+			MaxLines: output.MaxLines,
+			Strategy: output.Strategy,
+		}
+	}
+
+	if len(confirmations) > 0 {
+		doc.Confirm = make(map[string]string, len(confirmations))
+		for name, policy := range confirmations {
+			doc.Confirm[name] = policy.String()
+		}
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(broDir, ModelsConfigFile), data, 0644)
+}
+
+// UpdateModels (re)writes ~/.bro/config.yaml with the default model list,
+// discarding any aliases or overrides a user had configured. It's what
+// InitializeBroDirectory falls back to on a fresh install (no config.yaml,
+// no models.txt to migrate) and what the TUI's /update-models command
+// calls directly.
+func UpdateModels() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	broDir := filepath.Join(homeDir, ".bro")
+
+	if err := writeModelsConfig(broDir, defaultModelEntries(), ToolPolicy{}, Defaults{}, tools.OutputPolicy{}, nil); err != nil {
+		return err
+	}
+
+	log.Info("Updated config.yaml successfully")
+	return nil
+}
+
+// UpdateAvailableModels reloads config's Models/Tools/Defaults/
+// AvailableModels from ~/.bro/config.yaml, e.g. after UpdateModels wrote
+// a fresh one.
+func (c *Config) UpdateAvailableModels() error {
+	return loadModelsConfig(c)
+}
+
+// parseModelsYAML parses config.yaml via yaml.v3 into the same shape
+// loadModelsConfig hands to Config: a top-level "models:" list (each
+// entry optionally carrying a nested "tools:" allow/deny map), plus
+// top-level "tools:", "defaults:", "output:", and "confirm:" sections.
+func parseModelsYAML(data []byte) ([]ModelEntry, ToolPolicy, Defaults, tools.OutputPolicy, map[string]agents.ToolPolicy, error) {
+	var doc yamlModelsConfig
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, ToolPolicy{}, Defaults{}, tools.OutputPolicy{}, nil, err
+	}
+
+	models := make([]ModelEntry, len(doc.Models))
+	for i, m := range doc.Models {
+		models[i] = ModelEntry{
+			Alias:        m.Alias,
+			Model:        m.Model,
+			Temperature:  m.Temperature,
+			MaxTokens:    m.MaxTokens,
+			SystemPrompt: m.SystemPrompt,
+			Tools:        fromYAMLToolPolicy(m.Tools),
+		}
+	}
+
+	globalTools := fromYAMLToolPolicy(doc.Tools)
+
+	var defaults Defaults
+	if doc.Defaults != nil {
+		defaults = Defaults{
+			Active:      doc.Defaults.Active,
+			Temperature: doc.Defaults.Temperature,
+			MaxTokens:   doc.Defaults.MaxTokens,
+			Provider:    doc.Defaults.Provider,
+		}
+	}
+
+	var output tools.OutputPolicy
+	if doc.Output != nil {
+		output = tools.OutputPolicy{
+			MaxBytes: doc.Output.MaxBytes,
+			MaxLines: doc.Output.MaxLines,
+			Strategy: doc.Output.Strategy,
+		}
+	}
+
+	var confirmations map[string]agents.ToolPolicy
+	for tool, value := range doc.Confirm {
+		policy, err := agents.ParsePolicy(value)
+		if err != nil {
+			log.Warn("Skipping invalid confirm policy in config.yaml", "tool", tool, "error", err)
+			continue
+		}
+		if confirmations == nil {
+			confirmations = make(map[string]agents.ToolPolicy)
+		}
+		confirmations[tool] = policy
+	}
+
+	return models, globalTools, defaults, output, confirmations, nil
+}
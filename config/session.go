@@ -1,21 +1,36 @@
 package config
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/openrouter"
 )
 
+// sessionSchemaVersion is bumped whenever SessionEntry.Content's shape
+// changes in a way LoadSession needs to know about. Entries written by a
+// newer version than this build understands are skipped (with a warning)
+// instead of breaking the rest of the replay.
+const sessionSchemaVersion = 1
+
 type SessionEntry struct {
+	Version   int         `json:"version"`
 	Timestamp time.Time   `json:"timestamp"`
 	Type      string      `json:"type"` // "user_input", "ai_response", "tool_call"
 	Content   interface{} `json:"content"`
 }
 
+// sessionDirLayout is the time.Parse/Format layout matching the
+// "DDMon_YYYY" directory names NewSession creates (e.g. "29Jul_2026").
+const sessionDirLayout = "02Jan_2006"
+
 type Session struct {
 	dirPath     string
 	sessionFile *os.File
@@ -53,6 +68,13 @@ func NewSession() (*Session, error) {
 	}, nil
 }
 
+// Dir returns the directory this session's log file lives in
+// (~/.bro/DDMon_YYYY), so callers resolving a bare "/resume HH_MM.jsonl"
+// argument know where to look.
+func (s *Session) Dir() string {
+	return s.dirPath
+}
+
 func (s *Session) LogUserInput(input string) error {
 	entry := SessionEntry{
 		Timestamp: time.Now(),
@@ -85,6 +107,22 @@ func (s *Session) LogAIResponseWithToolCalls(response string, toolCalls []interf
 	return s.writeEntry(entry)
 }
 
+// LogBranchReset records that the live app truncated its message history
+// back to keepCount messages (app.App's edit-and-resend - see
+// app/branch.go's beginEditLastMessage), just before the user_input entry
+// for the resent message. LoadSession replays it by truncating the
+// transcript it's rebuilding to the same length, so resuming a session
+// reproduces the branch that was active when it was last written instead of
+// replaying the abandoned original turn too.
+func (s *Session) LogBranchReset(keepCount int) error {
+	entry := SessionEntry{
+		Timestamp: time.Now(),
+		Type:      "branch_reset",
+		Content:   keepCount,
+	}
+	return s.writeEntry(entry)
+}
+
 func (s *Session) LogToolCall(toolName string, params interface{}, result interface{}) error {
 	toolCall := map[string]interface{}{
 		"tool_name":  toolName,
@@ -106,6 +144,8 @@ func (s *Session) writeEntry(entry SessionEntry) error {
 		return fmt.Errorf("session file is not initialized")
 	}
 
+	entry.Version = sessionSchemaVersion
+
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return err
@@ -125,3 +165,258 @@ func (s *Session) Close() error {
 	}
 	return nil
 }
+
+// SessionBranch is one sibling snapshot recovered by LoadSession: the full
+// message slate as it stood right before a "branch_reset" entry truncated
+// it (or, for the last element, at end of file). Branches[i] is what
+// app.App's own turnBranch held in memory for the same edit-and-resend
+// chain before the process exited - see LoadSession's doc comment for
+// how replay reconstructs it from an otherwise-linear log.
+type SessionBranch struct {
+	Messages []openrouter.Renderable
+}
+
+// LoadSession reads a HH_MM.jsonl session log written by NewSession's
+// Log* methods and replays it back into the Renderable transcript App
+// would have built live: this is the reverse of
+// openrouter.ChatMessagesToOpenRouter. It's the basis for both the
+// `/resume` command and the --resume-session launch flag.
+//
+// Alongside the final transcript, it returns every sibling branch an
+// edit-and-resend (app/branch.go) produced along the way: each
+// "branch_reset" entry truncates the message slate being built, but
+// first the pre-truncation slate is snapshotted as a completed branch, so
+// the full edit history - not just its last branch - survives a restart
+// instead of being discarded the way a single flat replay would discard
+// it.
+func LoadSession(path string) ([]openrouter.Renderable, []SessionBranch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var messages []openrouter.Renderable
+	var pendingToolCalls []openrouter.ToolCall
+	var branches []SessionBranch
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry SessionEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, nil, fmt.Errorf("decoding session entry: %w", err)
+		}
+		if entry.Version > sessionSchemaVersion {
+			log.Warn("Skipping session entry from a newer schema version", "version", entry.Version)
+			continue
+		}
+
+		switch entry.Type {
+		case "user_input":
+			text, _ := entry.Content.(string)
+			messages = append(messages, openrouter.NewUserMessage(text))
+
+		case "ai_response":
+			response, toolCalls := decodeAIResponse(entry.Content)
+			if response != "" {
+				messages = append(messages, openrouter.NewAssistantMessage(response, ""))
+			}
+			pendingToolCalls = append(pendingToolCalls, toolCalls...)
+
+		case "tool_call":
+			toolName, arguments, result := decodeToolCall(entry.Content)
+			toolCall := takeToolCall(&pendingToolCalls, toolName, arguments)
+			messages = append(messages, &openrouter.ToolCallMessage{ToolCall: toolCall})
+			messages = append(messages, &openrouter.ToolResponseMessage{
+				ToolCallID: toolCall.ID,
+				ToolName:   toolName,
+				Result:     result,
+			})
+
+		case "branch_reset":
+			if keep, ok := entry.Content.(float64); ok && int(keep) >= 0 && int(keep) <= len(messages) {
+				branches = append(branches, SessionBranch{Messages: cloneRenderables(messages)})
+				messages = messages[:int(keep)]
+				pendingToolCalls = nil
+			}
+
+		default:
+			log.Warn("Skipping unrecognized session entry type", "type", entry.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(branches) > 0 {
+		branches = append(branches, SessionBranch{Messages: cloneRenderables(messages)})
+	}
+
+	return messages, branches, nil
+}
+
+func cloneRenderables(messages []openrouter.Renderable) []openrouter.Renderable {
+	cloned := make([]openrouter.Renderable, len(messages))
+	copy(cloned, messages)
+	return cloned
+}
+
+// decodeAIResponse recovers an "ai_response" entry's response text and the
+// tool calls it requested (see LogAIResponseWithToolCalls) from the
+// interface{} json.Unmarshal produced. Older entries written by the
+// unused-but-still-exported LogAIResponse are a bare string instead of a
+// map, and are handled the same as a response with no tool calls.
+func decodeAIResponse(content interface{}) (response string, toolCalls []openrouter.ToolCall) {
+	switch c := content.(type) {
+	case string:
+		return c, nil
+	case map[string]interface{}:
+		response, _ = c["response"].(string)
+		raw, _ := c["tool_calls"].([]interface{})
+		for _, r := range raw {
+			entry, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := entry["id"].(string)
+			typ, _ := entry["type"].(string)
+			name, _ := entry["function"].(string)
+			arguments, _ := entry["arguments"].(string)
+			toolCalls = append(toolCalls, openrouter.ToolCall{
+				ID:   id,
+				Type: typ,
+				Function: openrouter.ToolCallFunction{
+					Name:      name,
+					Arguments: arguments,
+				},
+			})
+		}
+		return response, toolCalls
+	default:
+		return "", nil
+	}
+}
+
+// decodeToolCall recovers a "tool_call" entry's fields (see LogToolCall).
+func decodeToolCall(content interface{}) (toolName, arguments, result string) {
+	c, ok := content.(map[string]interface{})
+	if !ok {
+		return "", "", ""
+	}
+	toolName, _ = c["tool_name"].(string)
+	arguments, _ = c["parameters"].(string)
+	result, _ = c["result"].(string)
+	return toolName, arguments, result
+}
+
+// takeToolCall removes and returns the first entry in pending whose
+// function name matches toolName, so a "tool_call" log entry (which only
+// records name/arguments/result) can recover the ID/Type its preceding
+// "ai_response" entry's tool_calls carried. Falls back to a synthetic
+// ToolCall built from name/arguments alone if no match is found, e.g. for
+// a log truncated mid-write.
+func takeToolCall(pending *[]openrouter.ToolCall, toolName, arguments string) openrouter.ToolCall {
+	for i, tc := range *pending {
+		if tc.Function.Name == toolName {
+			*pending = append((*pending)[:i], (*pending)[i+1:]...)
+			return tc
+		}
+	}
+	return openrouter.ToolCall{
+		Type:     "function",
+		Function: openrouter.ToolCallFunction{Name: toolName, Arguments: arguments},
+	}
+}
+
+// ListSessionDays returns every session day directory under ~/.bro (the
+// "DDMon_YYYY" directories NewSession creates), oldest first. A missing
+// ~/.bro directory is not an error - it just means no sessions exist yet.
+func ListSessionDays() ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(homeDir, ".bro"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	type day struct {
+		name string
+		t    time.Time
+	}
+	var days []day
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := time.Parse(sessionDirLayout, e.Name())
+		if err != nil {
+			continue // not a session day directory
+		}
+		days = append(days, day{e.Name(), t})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].t.Before(days[j].t) })
+
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = d.name
+	}
+	return names, nil
+}
+
+// ListSessionFiles returns the .jsonl session file paths within dayDir (a
+// name returned by ListSessionDays), oldest first - HH_MM.jsonl sorts
+// chronologically as a plain string.
+func ListSessionFiles(dayDir string) ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	dirPath := filepath.Join(homeDir, ".bro", dayDir)
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		files = append(files, filepath.Join(dirPath, e.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// MostRecentSessionFile returns the path to the latest session file across
+// every day directory, or "" if none exist yet.
+func MostRecentSessionFile() (string, error) {
+	days, err := ListSessionDays()
+	if err != nil || len(days) == 0 {
+		return "", err
+	}
+
+	for i := len(days) - 1; i >= 0; i-- {
+		files, err := ListSessionFiles(days[i])
+		if err != nil {
+			return "", err
+		}
+		if len(files) > 0 {
+			return files[len(files)-1], nil
+		}
+	}
+	return "", nil
+}
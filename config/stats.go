@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/log"
+	brofs "github.com/festeh/bro/fs"
 	"github.com/revrost/go-openrouter"
 )
 
@@ -24,27 +25,41 @@ type Stats struct {
 	dirPath     string
 	currentDate string
 	dailyStats  *DailyStats
+	fs          brofs.FS
+	history     *History
 }
 
+// SetHistory connects Stats to a History so that AddUsage also records the
+// request's cost/tokens against the current history entry.
+func (s *Stats) SetHistory(history *History) {
+	s.history = history
+}
+
+// NewStats creates a Stats tracker backed by the real filesystem, rooted at
+// ~/.bro/stats.
 func NewStats() (*Stats, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	broDir := filepath.Join(homeDir, ".bro")
-	statsDir := filepath.Join(broDir, "stats")
+	return NewStatsWithFS(brofs.NewOSFs(), filepath.Join(homeDir, ".bro", "stats"))
+}
 
+// NewStatsWithFS creates a Stats tracker backed by the given filesystem,
+// rooted at statsDir. Tests can pass brofs.NewMemFs() to avoid touching disk.
+func NewStatsWithFS(filesystem brofs.FS, statsDir string) (*Stats, error) {
 	// Create stats directory if it doesn't exist
-	if err := os.MkdirAll(statsDir, 0755); err != nil {
+	if err := filesystem.MkdirAll(statsDir, 0755); err != nil {
 		return nil, err
 	}
 
 	currentDate := time.Now().Format("2006-01-02")
-	
+
 	stats := &Stats{
 		dirPath:     statsDir,
 		currentDate: currentDate,
+		fs:          filesystem,
 	}
 
 	// Load or create today's stats
@@ -57,9 +72,9 @@ func NewStats() (*Stats, error) {
 
 func (s *Stats) loadTodaysStats() error {
 	statsFile := filepath.Join(s.dirPath, fmt.Sprintf("%s.json", s.currentDate))
-	
+
 	// Check if today's stats file exists
-	if _, err := os.Stat(statsFile); os.IsNotExist(err) {
+	if _, err := s.fs.Stat(statsFile); os.IsNotExist(err) {
 		// Create new daily stats
 		s.dailyStats = &DailyStats{
 			Date:             s.currentDate,
@@ -73,7 +88,7 @@ func (s *Stats) loadTodaysStats() error {
 	}
 
 	// Load existing stats
-	data, err := os.ReadFile(statsFile)
+	data, err := s.fs.ReadFile(statsFile)
 	if err != nil {
 		return err
 	}
@@ -99,7 +114,7 @@ func (s *Stats) saveTodaysStats() error {
 		return err
 	}
 
-	return os.WriteFile(statsFile, data, 0644)
+	return s.fs.WriteFile(statsFile, data, 0644)
 }
 
 func (s *Stats) AddUsage(usage *openrouter.Usage) error {
@@ -129,6 +144,12 @@ func (s *Stats) AddUsage(usage *openrouter.Usage) error {
 	s.dailyStats.TotalCost += usage.Cost
 	s.dailyStats.RequestCount++
 
+	if s.history != nil {
+		if err := s.history.UpdateLastRecord("", usage.TotalTokens, usage.Cost, 0); err != nil {
+			log.Error("Failed to update history record with usage", "error", err)
+		}
+	}
+
 	// Save updated stats
 	return s.saveTodaysStats()
 }
@@ -0,0 +1,37 @@
+// Package fs abstracts filesystem access behind a minimal interface so
+// callers like config.History, config.Stats, and fileedit.Tool can be
+// tested against an in-memory filesystem instead of the real ~/.bro
+// directory, following the spf13/afero split between a real-OS backend
+// and a fake one.
+package fs
+
+import (
+	"io"
+	"os"
+)
+
+// File is the subset of *os.File that FS implementations hand back.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// FS is a minimal filesystem interface covering what the rest of bro
+// needs: reading and writing whole files, opening a file for streaming
+// access, and the directory/removal operations config and fileedit rely on.
+type FS interface {
+	Open(name string) (File, error)
+	Create(name string) (File, error)
+	Stat(name string) (os.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	// WriteFileAtomic is like WriteFile but, on a real filesystem, never
+	// leaves a reader able to observe a partially-written file - see
+	// modifyfile.Tool, which needs this so a crash mid-write can't corrupt
+	// the file it's editing.
+	WriteFileAtomic(name string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(name string) error
+}
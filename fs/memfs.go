@@ -0,0 +1,141 @@
+package fs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MemFs is an in-memory FS implementation for unit tests, so they no
+// longer need to juggle t.TempDir or touch the real ~/.bro directory.
+type MemFs struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// NewMemFs creates an empty in-memory filesystem.
+func NewMemFs() *MemFs {
+	return &MemFs{
+		files: make(map[string][]byte),
+		dirs:  map[string]bool{".": true},
+	}
+}
+
+type memFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return fi.isDir }
+func (fi memFileInfo) Sys() interface{}   { return nil }
+
+// memFile is a read/write handle into a MemFs entry. Writes accumulate in
+// buf and are only committed back to the owning MemFs on Close, matching
+// how os.File behaves with buffered writers.
+type memFile struct {
+	name    string
+	fs      *MemFs
+	buf     *bytes.Buffer
+	written bool
+}
+
+func (f *memFile) Read(p []byte) (int, error) { return f.buf.Read(p) }
+func (f *memFile) Write(p []byte) (int, error) {
+	f.written = true
+	return f.buf.Write(p)
+}
+
+func (f *memFile) Close() error {
+	if !f.written {
+		return nil
+	}
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	f.fs.files[f.name] = append([]byte(nil), f.buf.Bytes()...)
+	return nil
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return memFileInfo{name: filepath.Base(f.name), size: int64(len(f.fs.files[f.name]))}, nil
+}
+
+func (m *MemFs) Open(name string) (File, error) {
+	m.mu.Lock()
+	content, ok := m.files[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &memFile{name: name, fs: m, buf: bytes.NewBuffer(append([]byte(nil), content...))}, nil
+}
+
+func (m *MemFs) Create(name string) (File, error) {
+	m.mu.Lock()
+	m.files[name] = nil
+	m.mu.Unlock()
+	return &memFile{name: name, fs: m, buf: &bytes.Buffer{}}, nil
+}
+
+func (m *MemFs) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), isDir: true}, nil
+	}
+	content, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memFileInfo{name: filepath.Base(name), size: int64(len(content))}, nil
+}
+
+func (m *MemFs) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), content...), nil
+}
+
+func (m *MemFs) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// WriteFileAtomic is equivalent to WriteFile here: every MemFs write
+// already replaces the entry in one lock-held step, so there's no
+// partial-write state for a temp-file-and-rename to avoid.
+func (m *MemFs) WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	return m.WriteFile(name, data, perm)
+}
+
+func (m *MemFs) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs[path] = true
+	return nil
+}
+
+func (m *MemFs) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
@@ -0,0 +1,85 @@
+package fs
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMemFsWriteFileAndReadFile(t *testing.T) {
+	m := NewMemFs()
+
+	if err := m.WriteFile("note.txt", []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	content, err := m.ReadFile("note.txt")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(content) != "hello" {
+		t.Errorf("Expected 'hello', got %q", content)
+	}
+}
+
+func TestMemFsReadFileMissing(t *testing.T) {
+	m := NewMemFs()
+
+	if _, err := m.ReadFile("missing.txt"); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
+
+func TestMemFsCreateThenOpen(t *testing.T) {
+	m := NewMemFs()
+
+	f, err := m.Create("log.txt")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := f.Write([]byte("line 1\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	opened, err := m.Open("log.txt")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer opened.Close()
+
+	buf := make([]byte, 64)
+	n, _ := opened.Read(buf)
+	if string(buf[:n]) != "line 1\n" {
+		t.Errorf("Expected 'line 1\\n', got %q", buf[:n])
+	}
+}
+
+func TestMemFsStat(t *testing.T) {
+	m := NewMemFs()
+	_ = m.WriteFile("a.txt", []byte("abc"), 0644)
+
+	info, err := m.Stat("a.txt")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", info.Size())
+	}
+	if info.IsDir() {
+		t.Error("Expected a.txt to not be a directory")
+	}
+}
+
+func TestMemFsRemove(t *testing.T) {
+	m := NewMemFs()
+	_ = m.WriteFile("a.txt", []byte("abc"), 0644)
+
+	if err := m.Remove("a.txt"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := m.ReadFile("a.txt"); !os.IsNotExist(err) {
+		t.Errorf("Expected not-exist error after removal, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// OSFs implements FS by delegating directly to the os package. It is the
+// default filesystem used outside of tests.
+type OSFs struct{}
+
+// NewOSFs creates an FS backed by the real filesystem.
+func NewOSFs() *OSFs {
+	return &OSFs{}
+}
+
+func (OSFs) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+func (OSFs) Create(name string) (File, error) {
+	return os.Create(name)
+}
+
+func (OSFs) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFs) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OSFs) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// WriteFileAtomic writes data to a temp file in name's directory, then
+// renames it over name, so a reader never observes a partially-written
+// file and a crash mid-write leaves the original untouched. The rename
+// is only atomic within a single filesystem, which is why the temp file
+// is created alongside name rather than in the system temp directory.
+func (OSFs) WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, name)
+}
+
+func (OSFs) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OSFs) Remove(name string) error {
+	return os.Remove(name)
+}
@@ -0,0 +1,44 @@
+package toolfake
+
+import (
+	"github.com/festeh/bro/tools/fileedit"
+	"github.com/festeh/bro/tools/filepatch"
+	"github.com/festeh/bro/tools/readfile"
+)
+
+// Editor sequences the read-then-edit cycle a real agent follows when
+// changing a file it hasn't seen yet in this turn: readfile first, then
+// one or more fileedit/filepatch calls against the same path, all
+// recorded in the owning Sandbox's transcript like any other call.
+type Editor struct {
+	sb   *Sandbox
+	path string
+}
+
+// Open starts an edit cycle on relPath by reading its current content
+// through the readfile tool, then returns an Editor scoped to it.
+func (sb *Sandbox) Open(relPath string) *Editor {
+	sb.t.Helper()
+	sb.Run("readfile", readfile.Args{Path: sb.Path(relPath)})
+	return &Editor{sb: sb, path: relPath}
+}
+
+// Edit applies a fileedit old_string/new_string replacement to the file
+// this Editor has open.
+func (e *Editor) Edit(oldString, newString string) string {
+	e.sb.t.Helper()
+	return e.sb.Run("fileedit", fileedit.Args{
+		Path:      e.sb.Path(e.path),
+		OldString: oldString,
+		NewString: newString,
+	})
+}
+
+// Patch applies a unified diff to the file this Editor has open. patch's
+// own "--- "/"+++ " headers must reference e's path (see Sandbox.Path) -
+// Patch doesn't rewrite them, since a patch covering several files needs
+// to name each one itself.
+func (e *Editor) Patch(patch string, fuzz int) string {
+	e.sb.t.Helper()
+	return e.sb.Run("filepatch", filepatch.Args{Patch: patch, Fuzz: fuzz})
+}
@@ -0,0 +1,137 @@
+// Package toolfake provides a deterministic sandbox for testing bro's
+// tools end-to-end without a real LLM: a real temporary directory seeded
+// from an in-memory file tree, a tools.Registry rooted there, and helpers
+// to run a tool, assert on the files it left behind, and capture the
+// transcript of every call made for golden-style tests.
+//
+// Sandbox uses a real directory rather than brofs.MemFs because not every
+// tool goes through brofs.FS - grep and filefinder shell out to rg/fd
+// against the real filesystem - so a fake in-memory one couldn't back
+// every tool the registry carries.
+package toolfake
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/festeh/bro/tools"
+	"github.com/festeh/bro/tools/dirtree"
+	"github.com/festeh/bro/tools/fileedit"
+	"github.com/festeh/bro/tools/filefinder"
+	"github.com/festeh/bro/tools/filepatch"
+	"github.com/festeh/bro/tools/grep"
+	"github.com/festeh/bro/tools/modifyfile"
+	"github.com/festeh/bro/tools/readfile"
+)
+
+// Call is one tool invocation recorded in a Sandbox's transcript.
+type Call struct {
+	Tool   string
+	Args   interface{}
+	Result string
+}
+
+// Sandbox is a temporary workdir backing a tools.Registry, for tests that
+// want to drive real tools against real files without touching the
+// developer's own filesystem.
+type Sandbox struct {
+	t        testing.TB
+	Root     string
+	Registry *tools.Registry
+	calls    []Call
+}
+
+// New creates a Sandbox rooted at a fresh temporary directory seeded with
+// files (keyed by path relative to the root), then registers the
+// filesystem-facing tools agents.BuildToolRegistry otherwise draws from
+// (bash, watcher, and the lsp_* tools are left out - bash and the lsp_*
+// tools depend on a shell and spawned language servers rather than just
+// files, so they don't fit this harness). The directory and every file in it are removed automatically
+// when the test finishes.
+func New(t testing.TB, files map[string]string) *Sandbox {
+	t.Helper()
+	root := t.TempDir()
+
+	for relPath, content := range files {
+		abs := filepath.Join(root, relPath)
+		if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+			t.Fatalf("toolfake: creating directory for %q: %v", relPath, err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0644); err != nil {
+			t.Fatalf("toolfake: writing %q: %v", relPath, err)
+		}
+	}
+
+	registry := tools.NewRegistry()
+	toolCtx := tools.DefaultToolContext()
+	for _, tool := range []tools.Tool{
+		dirtree.NewTool(),
+		fileedit.NewTool(),
+		filefinder.NewToolWithContext(toolCtx),
+		filepatch.NewTool(),
+		grep.NewToolWithContext(toolCtx),
+		modifyfile.NewTool(),
+		readfile.NewToolWithContext(toolCtx),
+	} {
+		registry.Register(tool)
+	}
+
+	return &Sandbox{t: t, Root: root, Registry: registry}
+}
+
+// Path resolves relPath against the sandbox root, for building tool args
+// that need an absolute path.
+func (sb *Sandbox) Path(relPath string) string {
+	return filepath.Join(sb.Root, relPath)
+}
+
+// Run marshals args to JSON and executes tool by name against the
+// sandbox's registry, recording the call in its transcript. It fails the
+// test immediately if the tool itself returns an error - as opposed to an
+// "Error: ..." result string, which every bro tool uses for expected
+// failures (bad path, file not found, and so on) and which callers should
+// assert on directly.
+func (sb *Sandbox) Run(toolName string, args interface{}) string {
+	sb.t.Helper()
+	data, err := json.Marshal(args)
+	if err != nil {
+		sb.t.Fatalf("toolfake: marshaling args for %q: %v", toolName, err)
+	}
+
+	result, err := tools.ExecuteTool(context.Background(), sb.Registry, toolName, data, tools.NoopProgress)
+	if err != nil {
+		sb.t.Fatalf("toolfake: %q returned an error: %v", toolName, err)
+	}
+
+	sb.calls = append(sb.calls, Call{Tool: toolName, Args: args, Result: result})
+	return result
+}
+
+// ReadFile returns relPath's current content from the sandbox, failing
+// the test if it can't be read.
+func (sb *Sandbox) ReadFile(relPath string) string {
+	sb.t.Helper()
+	content, err := os.ReadFile(sb.Path(relPath))
+	if err != nil {
+		sb.t.Fatalf("toolfake: reading %q: %v", relPath, err)
+	}
+	return string(content)
+}
+
+// AssertFile fails the test if relPath's content doesn't equal want.
+func (sb *Sandbox) AssertFile(relPath, want string) {
+	sb.t.Helper()
+	if got := sb.ReadFile(relPath); got != want {
+		sb.t.Errorf("toolfake: %q content = %q, want %q", relPath, got, want)
+	}
+}
+
+// Transcript returns every call made through Run so far, in order, for
+// golden-style tests that assert on a whole sequence of tool calls rather
+// than one result at a time.
+func (sb *Sandbox) Transcript() []Call {
+	return append([]Call(nil), sb.calls...)
+}
@@ -0,0 +1,62 @@
+package toolfake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSandboxRunAndAssertFile(t *testing.T) {
+	sb := New(t, map[string]string{
+		"a.txt": "one\ntwo\nthree\n",
+	})
+
+	result := sb.Run("fileedit", struct {
+		Path      string `json:"path"`
+		OldString string `json:"old_string"`
+		NewString string `json:"new_string"`
+	}{Path: sb.Path("a.txt"), OldString: "two", NewString: "TWO"})
+
+	if !strings.Contains(result, "Successfully edited file") {
+		t.Errorf("Expected a success message, got: %s", result)
+	}
+	sb.AssertFile("a.txt", "one\nTWO\nthree\n")
+}
+
+func TestSandboxTranscript(t *testing.T) {
+	sb := New(t, map[string]string{"a.txt": "hello\n"})
+
+	sb.Run("readfile", struct {
+		Path string `json:"path"`
+	}{Path: sb.Path("a.txt")})
+
+	transcript := sb.Transcript()
+	if len(transcript) != 1 {
+		t.Fatalf("Expected 1 recorded call, got %d", len(transcript))
+	}
+	if transcript[0].Tool != "readfile" {
+		t.Errorf("Expected transcript to record the readfile call, got %q", transcript[0].Tool)
+	}
+	if !strings.Contains(transcript[0].Result, "hello") {
+		t.Errorf("Expected the recorded result to contain the file's content, got: %s", transcript[0].Result)
+	}
+}
+
+func TestEditorOpenAndEdit(t *testing.T) {
+	sb := New(t, map[string]string{"a.txt": "before\n"})
+
+	sb.Open("a.txt").Edit("before", "after")
+	sb.AssertFile("a.txt", "after\n")
+
+	transcript := sb.Transcript()
+	if len(transcript) != 2 || transcript[0].Tool != "readfile" || transcript[1].Tool != "fileedit" {
+		t.Fatalf("Expected a readfile call followed by a fileedit call, got: %+v", transcript)
+	}
+}
+
+func TestEditorPatch(t *testing.T) {
+	sb := New(t, map[string]string{"a.txt": "one\ntwo\nthree\n"})
+
+	patch := "--- a" + sb.Path("a.txt") + "\n+++ b" + sb.Path("a.txt") + "\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"
+	sb.Open("a.txt").Patch(patch, 1)
+	sb.AssertFile("a.txt", "one\nTWO\nthree\n")
+}
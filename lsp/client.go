@@ -0,0 +1,256 @@
+// Package lsp connects bro to language servers (gopls, pyright,
+// rust-analyzer, ...) over their standard JSON-RPC-over-stdio wire format,
+// so tools can ask a project's own compiler semantic questions instead of
+// string-matching with grep. client.go speaks that wire format; manager.go
+// owns server lifecycle and the open-file cache; the remaining files each
+// implement one tools.Tool (definition, references, hover, rename,
+// diagnostics, symbols) on top of it.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("lsp error %d: %s", e.Code, e.Message)
+}
+
+type rpcEnvelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// client is a JSON-RPC connection to a single spawned language server
+// process, framed the way every LSP server expects:
+// "Content-Length: N\r\n\r\n" followed by N bytes of JSON.
+type client struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan rpcEnvelope
+
+	diagMu      sync.Mutex
+	diagCond    *sync.Cond
+	diagnostics map[string][]Diagnostic // uri -> most recently published
+	diagSeen    map[string]bool         // uri -> at least one publish received
+}
+
+func startClient(command string, args []string, dir string) (*client, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Dir = dir
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = io.Discard
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", command, err)
+	}
+
+	c := &client{
+		cmd:         cmd,
+		stdin:       stdin,
+		pending:     make(map[int]chan rpcEnvelope),
+		diagnostics: make(map[string][]Diagnostic),
+		diagSeen:    make(map[string]bool),
+	}
+	c.diagCond = sync.NewCond(&c.diagMu)
+	go c.readLoop(bufio.NewReader(stdout))
+	return c, nil
+}
+
+func (c *client) readLoop(r *bufio.Reader) {
+	for {
+		body, err := readFrame(r)
+		if err != nil {
+			return
+		}
+
+		var env rpcEnvelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			continue
+		}
+
+		if env.Method == "textDocument/publishDiagnostics" {
+			c.handlePublishDiagnostics(env.Params)
+			continue
+		}
+		if env.ID == nil {
+			continue // notification we don't care about
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[*env.ID]
+		delete(c.pending, *env.ID)
+		c.mu.Unlock()
+		if ok {
+			ch <- env
+		}
+	}
+}
+
+func (c *client) handlePublishDiagnostics(raw json.RawMessage) {
+	var params PublishDiagnosticsParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return
+	}
+	c.diagMu.Lock()
+	c.diagnostics[params.URI] = params.Diagnostics
+	c.diagSeen[params.URI] = true
+	c.diagMu.Unlock()
+	c.diagCond.Broadcast()
+}
+
+// waitForDiagnostics blocks until at least one textDocument/publishDiagnostics
+// notification has arrived for uri, or timeout elapses - diagnostics are
+// pushed asynchronously after textDocument/didOpen, so there's otherwise no
+// way to know whether an empty result means "clean file" or "server hasn't
+// gotten to it yet".
+func (c *client) waitForDiagnostics(uri string, timeout time.Duration) []Diagnostic {
+	deadline := time.Now().Add(timeout)
+
+	c.diagMu.Lock()
+	defer c.diagMu.Unlock()
+
+	for !c.diagSeen[uri] {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		timer := time.AfterFunc(remaining, c.diagCond.Broadcast)
+		c.diagCond.Wait()
+		timer.Stop()
+	}
+	return c.diagnostics[uri]
+}
+
+// call sends a request and blocks for its response, or until ctx is done -
+// a hung or slow-starting language server (gopls indexing a large repo, for
+// instance) would otherwise wedge the call forever with no way to cancel.
+// A response that arrives after ctx is done is still drained into pending's
+// channel and discarded, so readLoop never blocks delivering it.
+func (c *client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	ch := make(chan rpcEnvelope, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := c.write(rpcEnvelope{JSONRPC: "2.0", ID: &id, Method: method, Params: mustMarshal(params)}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return err
+	}
+
+	select {
+	case env := <-ch:
+		if env.Error != nil {
+			return env.Error
+		}
+		if result == nil || len(env.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(env.Result, result)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notify sends a one-way message with no response to wait for.
+func (c *client) notify(method string, params interface{}) error {
+	return c.write(rpcEnvelope{JSONRPC: "2.0", Method: method, Params: mustMarshal(params)})
+}
+
+func (c *client) write(env rpcEnvelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := fmt.Fprintf(c.stdin, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.stdin.Write(body)
+	return err
+}
+
+func (c *client) close() {
+	c.stdin.Close()
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// readFrame reads one "Content-Length: N\r\n\r\n<N bytes>" frame.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	var length int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break // blank line ends the headers
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %w", err)
+			}
+		}
+	}
+	if length == 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+type PositionArgs struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// DefinitionTool implements tools.Tool over textDocument/definition.
+type DefinitionTool struct {
+	manager *Manager
+}
+
+// NewDefinitionTool creates an lsp_definition tool backed by manager.
+func NewDefinitionTool(manager *Manager) *DefinitionTool {
+	return &DefinitionTool{manager: manager}
+}
+
+func (t *DefinitionTool) Name() string {
+	return "lsp_definition"
+}
+
+func (t *DefinitionTool) Description() string {
+	return `Jump to where a symbol is defined, using the project's own language server (gopls, pyright, rust-analyzer, ...) instead of text search.
+
+Use this tool when you need to:
+- Find where a function, type, or variable is actually declared
+- Follow a call or reference back to its source, across files and packages
+- Resolve a symbol that grep would miss (renamed imports, generated code, interface satisfaction)
+
+Arguments: path (absolute file path), line and column (1-indexed) pointing at the symbol's use site.
+
+Returns one "path:line: snippet" entry per definition found, the same format readfile/grep use, so the result can be chained straight into another tool call.`
+}
+
+func (t *DefinitionTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a PositionArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	var locations []Location
+	if err := srv.client.call(ctx, "textDocument/definition", positionParams(uri, a.Line, a.Column), &locations); err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	return renderLocations(locations), nil
+}
+
+func (t *DefinitionTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  positionArgsSchema(),
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// diagnosticsTimeout bounds how long Execute waits for the server to
+// publish diagnostics after opening/syncing the file.
+const diagnosticsTimeout = 5 * time.Second
+
+type FileArgs struct {
+	Path string `json:"path"`
+}
+
+// DiagnosticsTool implements tools.Tool over the diagnostics a language
+// server pushes for an open file (errors, warnings, lints - whatever the
+// server itself reports), rather than issuing a request of its own.
+type DiagnosticsTool struct {
+	manager *Manager
+}
+
+// NewDiagnosticsTool creates an lsp_diagnostics tool backed by manager.
+func NewDiagnosticsTool(manager *Manager) *DiagnosticsTool {
+	return &DiagnosticsTool{manager: manager}
+}
+
+func (t *DiagnosticsTool) Name() string {
+	return "lsp_diagnostics"
+}
+
+func (t *DiagnosticsTool) Description() string {
+	return `Show the compiler/type-checker errors and warnings the project's own language server currently reports for a file - the same squiggly-underline diagnostics an editor would show, reflecting whatever is actually on disk right now.
+
+Use this tool when you need to:
+- Check whether an edit introduced a compile error or type error
+- See lint warnings without running a separate linter
+- Confirm a file is clean before considering a change finished
+
+Arguments: path (absolute file path).
+
+Returns one "path:line: [severity] message" entry per diagnostic, or "No diagnostics" if the file is clean.`
+}
+
+func (t *DiagnosticsTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a FileArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	diagnostics := srv.client.waitForDiagnostics(uri, diagnosticsTimeout)
+	if len(diagnostics) == 0 {
+		return "No diagnostics", nil
+	}
+
+	var out strings.Builder
+	for i, d := range diagnostics {
+		fmt.Fprintf(&out, "%s:%d: [%s] %s", a.Path, d.Range.Start.Line+1, severityName(d.Severity), d.Message)
+		if i < len(diagnostics)-1 {
+			out.WriteString("\n")
+		}
+	}
+	return out.String(), nil
+}
+
+func severityName(severity int) string {
+	switch severity {
+	case 1:
+		return "error"
+	case 2:
+		return "warning"
+	case 3:
+		return "info"
+	case 4:
+		return "hint"
+	default:
+		return "diagnostic"
+	}
+}
+
+func (t *DiagnosticsTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path to the source file",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
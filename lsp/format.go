@@ -0,0 +1,78 @@
+package lsp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// positionParams builds the line/column (converted from 1-indexed, as the
+// assistant gives them, to LSP's 0-indexed lines and characters) params
+// shared by definition/references/hover/rename. The column is only shifted
+// by one; it is not re-encoded as UTF-16 code units.
+func positionParams(uri string, line, column int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: line - 1, Character: column - 1},
+	}
+}
+
+// renderLocations formats locations in the file:line: snippet shape the
+// rest of bro's tools use, so the assistant can chain an lsp_* result
+// straight into readfile/grep without learning a new format.
+func renderLocations(locations []Location) string {
+	if len(locations) == 0 {
+		return "No results found"
+	}
+
+	var out strings.Builder
+	for i, loc := range locations {
+		path := uriToPath(loc.URI)
+		line := loc.Range.Start.Line + 1
+		fmt.Fprintf(&out, "%s:%d: %s", path, line, snippet(path, line))
+		if i < len(locations)-1 {
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+// snippet returns the trimmed source line at line (1-indexed), or "" if the
+// file can't be read or is shorter than that.
+func snippet(path string, line int) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if n == line {
+			return strings.TrimSpace(scanner.Text())
+		}
+	}
+	return ""
+}
+
+func positionArgsSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Absolute path to the source file",
+			},
+			"line": map[string]interface{}{
+				"type":        "integer",
+				"description": "1-indexed line number",
+			},
+			"column": map[string]interface{}{
+				"type":        "integer",
+				"description": "1-indexed column number",
+			},
+		},
+		"required": []string{"path", "line", "column"},
+	}
+}
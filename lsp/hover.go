@@ -0,0 +1,104 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// HoverTool implements tools.Tool over textDocument/hover.
+type HoverTool struct {
+	manager *Manager
+}
+
+// NewHoverTool creates an lsp_hover tool backed by manager.
+func NewHoverTool(manager *Manager) *HoverTool {
+	return &HoverTool{manager: manager}
+}
+
+func (t *HoverTool) Name() string {
+	return "lsp_hover"
+}
+
+func (t *HoverTool) Description() string {
+	return `Show the type signature and doc comment for the symbol at a position, the same information an editor's hover tooltip shows, straight from the project's own language server.
+
+Use this tool when you need to:
+- Check a function or method's exact signature and parameter types
+- Read a type or package's doc comment without opening its source file
+- Confirm what an inferred or generic type resolves to
+
+Arguments: path (absolute file path), line and column (1-indexed) pointing at the symbol.
+
+Returns the server's hover text, or "No hover information available" if it has nothing for that position.`
+}
+
+func (t *HoverTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a PositionArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	var hover Hover
+	if err := srv.client.call(ctx, "textDocument/hover", positionParams(uri, a.Line, a.Column), &hover); err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	text := hoverText(hover.Contents)
+	if text == "" {
+		return "No hover information available", nil
+	}
+	return text, nil
+}
+
+// hoverText normalizes the three shapes textDocument/hover's contents field
+// can take (a plain string, a MarkupContent object, or an array of either)
+// into one block of text.
+func hoverText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var asString string
+	if err := json.Unmarshal(raw, &asString); err == nil {
+		return strings.TrimSpace(asString)
+	}
+
+	var asMarkup MarkupContent
+	if err := json.Unmarshal(raw, &asMarkup); err == nil && asMarkup.Value != "" {
+		return strings.TrimSpace(asMarkup.Value)
+	}
+
+	var asArray []json.RawMessage
+	if err := json.Unmarshal(raw, &asArray); err == nil {
+		var parts []string
+		for _, item := range asArray {
+			if text := hoverText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n---\n")
+	}
+
+	return ""
+}
+
+func (t *HoverTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  positionArgsSchema(),
+		},
+	}
+}
@@ -0,0 +1,207 @@
+package lsp
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// serverSpec describes how to recognize and launch the language server for
+// one language.
+type serverSpec struct {
+	language string
+	command  string
+	args     []string
+	rootFile string // project-root marker, e.g. "go.mod"
+	exts     []string
+}
+
+// knownServers covers the ecosystems mentioned in the request; add entries
+// here to support another language.
+var knownServers = []serverSpec{
+	{language: "go", command: "gopls", args: []string{"serve"}, rootFile: "go.mod", exts: []string{".go"}},
+	{language: "python", command: "pyright-langserver", args: []string{"--stdio"}, rootFile: "pyproject.toml", exts: []string{".py"}},
+	{language: "rust", command: "rust-analyzer", rootFile: "Cargo.toml", exts: []string{".rs"}},
+	{language: "typescript", command: "typescript-language-server", args: []string{"--stdio"}, rootFile: "package.json", exts: []string{".ts", ".tsx", ".js", ".jsx"}},
+}
+
+// Manager is the long-lived owner of every language server bro has spawned
+// for the current session. Tools call forFile to get a ready-to-query
+// server for a source file; the manager lazily detects the project,
+// launches the server the first time it's needed, and keeps each server's
+// open-file cache synced with what's actually on disk.
+type Manager struct {
+	mu      sync.Mutex
+	servers map[string]*server   // keyed by language + root
+	files   map[string]*fileSync // keyed by server key + absolute path
+}
+
+type server struct {
+	spec   serverSpec
+	root   string
+	client *client
+}
+
+type fileSync struct {
+	version int
+	hash    [sha256.Size]byte
+}
+
+// NewManager creates a Manager with no servers running yet; they're spawned
+// on first use by forFile.
+func NewManager() *Manager {
+	return &Manager{
+		servers: make(map[string]*server),
+		files:   make(map[string]*fileSync),
+	}
+}
+
+// forFile returns the (lazily spawned) server that handles path, along with
+// path's file:// URI, after making sure the server's view of path matches
+// what's on disk right now. ctx bounds the server's initialize handshake
+// when it has to be spawned - gopls indexing a large repo can take a while,
+// and a caller cancelling shouldn't be left blocked on it.
+func (m *Manager) forFile(ctx context.Context, path string) (*server, string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	spec, ok := specForExt(filepath.Ext(absPath))
+	if !ok {
+		return nil, "", fmt.Errorf("no language server configured for %s", filepath.Ext(absPath))
+	}
+
+	root := findRoot(filepath.Dir(absPath), spec.rootFile)
+	key := spec.language + ":" + root
+
+	m.mu.Lock()
+	srv, ok := m.servers[key]
+	if !ok {
+		srv, err = spawnServer(ctx, spec, root)
+		if err != nil {
+			m.mu.Unlock()
+			return nil, "", err
+		}
+		m.servers[key] = srv
+	}
+	m.mu.Unlock()
+
+	uri := pathToURI(absPath)
+	if err := m.ensureOpen(srv, absPath, uri); err != nil {
+		return nil, "", err
+	}
+	return srv, uri, nil
+}
+
+func specForExt(ext string) (serverSpec, bool) {
+	for _, spec := range knownServers {
+		for _, candidate := range spec.exts {
+			if candidate == ext {
+				return spec, true
+			}
+		}
+	}
+	return serverSpec{}, false
+}
+
+// findRoot walks up from dir looking for marker, falling back to dir itself
+// if it's never found (e.g. a standalone script with no project file).
+func findRoot(dir, marker string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func spawnServer(ctx context.Context, spec serverSpec, root string) (*server, error) {
+	c, err := startClient(spec.command, spec.args, root)
+	if err != nil {
+		return nil, fmt.Errorf("launching %s language server: %w", spec.language, err)
+	}
+
+	initParams := map[string]interface{}{
+		"processId": os.Getpid(),
+		"rootUri":   pathToURI(root),
+		"capabilities": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"publishDiagnostics": map[string]interface{}{},
+			},
+		},
+	}
+	if err := c.call(ctx, "initialize", initParams, nil); err != nil {
+		c.close()
+		return nil, fmt.Errorf("initializing %s language server: %w", spec.language, err)
+	}
+	if err := c.notify("initialized", map[string]interface{}{}); err != nil {
+		c.close()
+		return nil, fmt.Errorf("initializing %s language server: %w", spec.language, err)
+	}
+
+	return &server{spec: spec, root: root, client: c}, nil
+}
+
+// ensureOpen sends textDocument/didOpen the first time path is seen, and
+// textDocument/didChange (full-document sync) whenever its on-disk content
+// has changed since - in particular right after fileedit/modifyfile write
+// to it, so diagnostics and query results never answer from stale content.
+func (m *Manager) ensureOpen(srv *server, absPath, uri string) error {
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", absPath, err)
+	}
+	hash := sha256.Sum256(content)
+
+	key := srv.spec.language + ":" + srv.root + ":" + absPath
+	m.mu.Lock()
+	sync, tracked := m.files[key]
+	m.mu.Unlock()
+
+	if tracked && sync.hash == hash {
+		return nil
+	}
+
+	if !tracked {
+		err = srv.client.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+			TextDocument: TextDocumentItem{URI: uri, LanguageID: srv.spec.language, Version: 1, Text: string(content)},
+		})
+		sync = &fileSync{version: 1, hash: hash}
+	} else {
+		sync.version++
+		sync.hash = hash
+		err = srv.client.notify("textDocument/didChange", DidChangeTextDocumentParams{
+			TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: sync.version},
+			ContentChanges: []TextDocumentContentChangeEvent{{Text: string(content)}},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.files[key] = sync
+	m.mu.Unlock()
+	return nil
+}
+
+func pathToURI(path string) string {
+	return "file://" + filepath.ToSlash(path)
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
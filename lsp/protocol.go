@@ -0,0 +1,139 @@
+package lsp
+
+import "encoding/json"
+
+// Minimal LSP 3.17 wire types - just the shapes the tools in this package
+// actually need, not the full spec.
+
+type Position struct {
+	Line      int `json:"line"`      // 0-indexed
+	Character int `json:"character"` // 0-indexed, UTF-16 code units
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"` // whole-document sync, no Range/RangeLength
+}
+
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context ReferenceContext `json:"context"`
+}
+
+type RenameParams struct {
+	TextDocumentPositionParams
+	NewName string `json:"newName"`
+}
+
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Hover.Contents can be a string, a {language, value} pair, or a
+// MarkupContent depending on the server and protocol version - leave it raw
+// and let hover.go sniff it.
+type Hover struct {
+	Contents json.RawMessage `json:"contents"`
+	Range    *Range          `json:"range,omitempty"`
+}
+
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// DocumentSymbol and SymbolInformation are the two shapes
+// textDocument/documentSymbol can return, depending on the server's
+// negotiated capability - symbols.go tries DocumentSymbol first and falls
+// back to SymbolInformation.
+type DocumentSymbol struct {
+	Name           string           `json:"name"`
+	Kind           int              `json:"kind"`
+	Range          Range            `json:"range"`
+	SelectionRange Range            `json:"selectionRange"`
+	Children       []DocumentSymbol `json:"children,omitempty"`
+}
+
+type SymbolInformation struct {
+	Name     string   `json:"name"`
+	Kind     int      `json:"kind"`
+	Location Location `json:"location"`
+}
+
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// symbolKindNames maps the LSP SymbolKind enum to a human-readable label;
+// unknown kinds just print their number.
+var symbolKindNames = map[int]string{
+	1: "file", 2: "module", 3: "namespace", 4: "package", 5: "class",
+	6: "method", 7: "property", 8: "field", 9: "constructor", 10: "enum",
+	11: "interface", 12: "function", 13: "variable", 14: "constant",
+	15: "string", 16: "number", 17: "boolean", 18: "array", 19: "object",
+	20: "key", 21: "null", 22: "enum member", 23: "struct", 24: "event",
+	25: "operator", 26: "type parameter",
+}
+
+func symbolKindName(kind int) string {
+	if name, ok := symbolKindNames[kind]; ok {
+		return name
+	}
+	return "symbol"
+}
@@ -0,0 +1,72 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// ReferencesTool implements tools.Tool over textDocument/references.
+type ReferencesTool struct {
+	manager *Manager
+}
+
+// NewReferencesTool creates an lsp_references tool backed by manager.
+func NewReferencesTool(manager *Manager) *ReferencesTool {
+	return &ReferencesTool{manager: manager}
+}
+
+func (t *ReferencesTool) Name() string {
+	return "lsp_references"
+}
+
+func (t *ReferencesTool) Description() string {
+	return `List every place a symbol is used, using the project's own language server instead of text search - so renamed imports, shadowed names, and generated code don't produce false positives or misses the way grep can.
+
+Use this tool when you need to:
+- Find every caller of a function before changing its signature
+- Check whether a type, field, or variable is still used anywhere
+- Understand the blast radius of a change before making it
+
+Arguments: path (absolute file path), line and column (1-indexed) pointing at the symbol's declaration or any use site.
+
+Returns one "path:line: snippet" entry per reference, the same format readfile/grep use.`
+}
+
+func (t *ReferencesTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a PositionArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	params := ReferenceParams{
+		TextDocumentPositionParams: positionParams(uri, a.Line, a.Column),
+		Context:                    ReferenceContext{IncludeDeclaration: true},
+	}
+
+	var locations []Location
+	if err := srv.client.call(ctx, "textDocument/references", params, &locations); err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	return renderLocations(locations), nil
+}
+
+func (t *ReferencesTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  positionArgsSchema(),
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+type RenameArgs struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	NewName string `json:"new_name"`
+}
+
+// RenameTool implements tools.Tool over textDocument/rename. It previews
+// the proposed edits rather than applying them - actually writing the
+// results is left to fileedit/modifyfile, which already own every write
+// path and their own confirmation/diff handling.
+type RenameTool struct {
+	manager *Manager
+}
+
+// NewRenameTool creates an lsp_rename tool backed by manager.
+func NewRenameTool(manager *Manager) *RenameTool {
+	return &RenameTool{manager: manager}
+}
+
+func (t *RenameTool) Name() string {
+	return "lsp_rename"
+}
+
+func (t *RenameTool) Description() string {
+	return `Preview a project-wide rename of the symbol at a position, computed by the project's own language server (which understands scope, shadowing, and cross-file references - grep-and-replace does not).
+
+Use this tool when you need to:
+- See every file and line a rename would touch before committing to it
+- Rename a function, type, field, or variable safely across a large codebase
+
+Arguments: path (absolute file path), line and column (1-indexed) pointing at the symbol, new_name (the replacement identifier).
+
+This tool does not modify any files - it only returns the proposed edits as "path:line: old -> new" entries. Apply them with fileedit/modifyfile once you've reviewed them.`
+}
+
+func (t *RenameTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a RenameArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	params := RenameParams{
+		TextDocumentPositionParams: positionParams(uri, a.Line, a.Column),
+		NewName:                    a.NewName,
+	}
+
+	var edit WorkspaceEdit
+	if err := srv.client.call(ctx, "textDocument/rename", params, &edit); err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	return renderWorkspaceEdit(edit), nil
+}
+
+func renderWorkspaceEdit(edit WorkspaceEdit) string {
+	if len(edit.Changes) == 0 {
+		return "No rename edits proposed"
+	}
+
+	uris := make([]string, 0, len(edit.Changes))
+	for uri := range edit.Changes {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	var out strings.Builder
+	for _, uri := range uris {
+		path := uriToPath(uri)
+		for _, e := range edit.Changes[uri] {
+			line := e.Range.Start.Line + 1
+			fmt.Fprintf(&out, "%s:%d: %s -> %s\n", path, line, strings.TrimSpace(snippet(path, line)), e.NewText)
+		}
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func (t *RenameTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path to the source file",
+					},
+					"line": map[string]interface{}{
+						"type":        "integer",
+						"description": "1-indexed line number",
+					},
+					"column": map[string]interface{}{
+						"type":        "integer",
+						"description": "1-indexed column number",
+					},
+					"new_name": map[string]interface{}{
+						"type":        "string",
+						"description": "The replacement identifier",
+					},
+				},
+				"required": []string{"path", "line", "column", "new_name"},
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// SymbolsTool implements tools.Tool over textDocument/documentSymbol.
+type SymbolsTool struct {
+	manager *Manager
+}
+
+// NewSymbolsTool creates an lsp_symbols tool backed by manager.
+func NewSymbolsTool(manager *Manager) *SymbolsTool {
+	return &SymbolsTool{manager: manager}
+}
+
+func (t *SymbolsTool) Name() string {
+	return "lsp_symbols"
+}
+
+func (t *SymbolsTool) Description() string {
+	return `List the functions, types, methods, and other top-level symbols declared in a file, using the project's own language server - an outline view, without reading the whole file.
+
+Use this tool when you need to:
+- Get the shape of an unfamiliar file before deciding what to read in full
+- Find which line a specific function or type is declared on
+- Check what a file exports/declares without grepping for keywords like "func" or "def"
+
+Arguments: path (absolute file path).
+
+Returns one "path:line: [kind] name" entry per symbol, the same format readfile/grep use.`
+}
+
+func (t *SymbolsTool) Execute(ctx context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var a FileArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return "", err
+	}
+
+	srv, uri, err := t.manager.forFile(ctx, a.Path)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	var raw []json.RawMessage
+	if err := srv.client.call(ctx, "textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+	}, &raw); err != nil {
+		return fmt.Sprintf("Error: %s", err), nil
+	}
+
+	lines := renderSymbols(a.Path, raw)
+	if len(lines) == 0 {
+		return "No symbols found", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// renderSymbols handles both shapes textDocument/documentSymbol can return:
+// nested DocumentSymbol (gopls, rust-analyzer) or flat SymbolInformation
+// (older servers) - it tries DocumentSymbol first per entry and falls back.
+func renderSymbols(path string, raw []json.RawMessage) []string {
+	var lines []string
+	for _, item := range raw {
+		var ds DocumentSymbol
+		if err := json.Unmarshal(item, &ds); err == nil && ds.Name != "" {
+			lines = append(lines, flattenDocumentSymbol(path, ds, "")...)
+			continue
+		}
+		var si SymbolInformation
+		if err := json.Unmarshal(item, &si); err == nil && si.Name != "" {
+			lines = append(lines, fmt.Sprintf("%s:%d: [%s] %s", path, si.Location.Range.Start.Line+1, symbolKindName(si.Kind), si.Name))
+		}
+	}
+	return lines
+}
+
+func flattenDocumentSymbol(path string, sym DocumentSymbol, indent string) []string {
+	lines := []string{fmt.Sprintf("%s:%d: [%s] %s%s", path, sym.SelectionRange.Start.Line+1, symbolKindName(sym.Kind), indent, sym.Name)}
+	for _, child := range sym.Children {
+		lines = append(lines, flattenDocumentSymbol(path, child, indent+"  ")...)
+	}
+	return lines
+}
+
+func (t *SymbolsTool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path to the source file",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
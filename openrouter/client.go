@@ -7,49 +7,55 @@ import (
 	"io"
 
 	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/agents"
 	"github.com/festeh/bro/environment"
+	"github.com/festeh/bro/provider"
 	"github.com/festeh/bro/tools"
-	"github.com/festeh/bro/tools/bash"
-	"github.com/festeh/bro/tools/fileedit"
-	"github.com/festeh/bro/tools/filefinder"
-	"github.com/festeh/bro/tools/grep"
-	"github.com/festeh/bro/tools/readfile"
 	"github.com/revrost/go-openrouter"
 )
 
-type StreamEvent struct {
-	Type      string
-	Content   string
-	Error     error
-	ToolCalls []ToolCall
-	Usage     *openrouter.Usage
-}
+// StreamEvent, ToolCall, ToolCallFunction, StreamHandler, and the
+// StreamEvent* constants now live in provider as the vendor-neutral
+// contract every backend streams through; they're aliased here so the
+// rest of this package (and App, which predates provider) keeps compiling
+// unchanged.
+type StreamEvent = provider.StreamEvent
+type ToolCall = provider.ToolCall
+type ToolCallFunction = provider.ToolCallFunction
+type StreamHandler = provider.StreamHandler
 
-type ToolCall struct {
-	Index    int              `json:"index"`
-	ID       string           `json:"id"`
-	Type     string           `json:"type"`
-	Function ToolCallFunction `json:"function"`
-}
+const (
+	StreamEventChunk    = provider.StreamEventChunk
+	StreamEventDone     = provider.StreamEventDone
+	StreamEventError    = provider.StreamEventError
+	StreamEventToolCall = provider.StreamEventToolCall
+	StreamEventUsage    = provider.StreamEventUsage
+)
 
-type ToolCallFunction struct {
-	Name      string `json:"name"`
-	Arguments string `json:"arguments"`
-}
+// DefaultMaxToolIterations bounds how many tool-result round trips a
+// single user turn may take when Config.MaxToolIterations is unset.
+const DefaultMaxToolIterations = provider.DefaultMaxToolIterations
+
+// Client is the openrouter.com implementation of provider.ChatCompletionProvider.
+var _ provider.ChatCompletionProvider = (*Client)(nil)
 
 const (
-	StreamEventChunk    = "chunk"
-	StreamEventDone     = "done"
-	StreamEventError    = "error"
-	StreamEventToolCall = "tool_call"
-	StreamEventUsage    = "usage"
+	defaultTemperature = 0.5
+	defaultMaxTokens   = 10000
 )
 
-type StreamHandler func(StreamEvent)
-
 type Config struct {
 	Model        string
 	ToolRegistry *tools.Registry
+	// MaxToolIterations bounds how many tool-call round trips a single
+	// user turn may take before the agent loop stops and waits for the
+	// user again. <= 0 means DefaultMaxToolIterations.
+	MaxToolIterations int
+	// Temperature and MaxTokens override SendMessages' request defaults
+	// (defaultTemperature, defaultMaxTokens); 0 means use the default.
+	// NewProvider fills these in from the active config.ModelEntry.
+	Temperature float64
+	MaxTokens   int
 }
 
 type Client struct {
@@ -65,14 +71,21 @@ func NewClient(env *environment.Environment, config *Config) (*Client, error) {
 		return nil, fmt.Errorf("valid config with model is required")
 	}
 
-	// Create default tool registry with tools if none provided
+	// Build the same full toolset agents.BuildToolRegistry assembles for
+	// an unrestricted agent if the caller didn't bring its own registry,
+	// rather than hand-listing tools here a second time and letting the
+	// two drift out of sync the way this fallback already had.
 	if config.ToolRegistry == nil {
-		config.ToolRegistry = tools.NewRegistry()
-		config.ToolRegistry.Register(bash.NewTool())
-		config.ToolRegistry.Register(fileedit.NewTool())
-		config.ToolRegistry.Register(filefinder.NewTool())
-		config.ToolRegistry.Register(grep.NewTool())
-		config.ToolRegistry.Register(readfile.NewTool())
+		config.ToolRegistry = agents.BuildToolRegistry(agents.Agent{}, tools.OutputPolicy{})
+	}
+	if config.MaxToolIterations <= 0 {
+		config.MaxToolIterations = DefaultMaxToolIterations
+	}
+	if config.Temperature == 0 {
+		config.Temperature = defaultTemperature
+	}
+	if config.MaxTokens == 0 {
+		config.MaxTokens = defaultMaxTokens
 	}
 
 	return &Client{
@@ -101,6 +114,12 @@ func (c *Client) GetModel() string {
 	return c.config.Model
 }
 
+// GetMaxToolIterations returns the configured bound on tool-call round
+// trips within a single turn.
+func (c *Client) GetMaxToolIterations() int {
+	return c.config.MaxToolIterations
+}
+
 func (c *Client) SendMessages(messages []openrouter.ChatCompletionMessage, handler StreamHandler) error {
 
 	// Get tools from the client's registry
@@ -110,8 +129,8 @@ func (c *Client) SendMessages(messages []openrouter.ChatCompletionMessage, handl
 		Model:       c.config.Model,
 		Messages:    messages,
 		Stream:      true,
-		Temperature: 0.5,
-		MaxTokens:   10000,
+		Temperature: float32(c.config.Temperature),
+		MaxTokens:   c.config.MaxTokens,
 		Tools:       tools,
 		Usage: &openrouter.IncludeUsage{
 			Include: true,
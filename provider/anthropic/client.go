@@ -0,0 +1,269 @@
+// Package anthropic implements provider.ChatCompletionProvider against
+// api.anthropic.com's streaming Messages endpoint, translating its native
+// tool-use content blocks (as opposed to OpenAI-style tool_calls deltas)
+// into provider.ToolCall events.
+package anthropic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/provider"
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+const (
+	defaultBaseURL   = "https://api.anthropic.com/v1"
+	anthropicVersion = "2023-06-01"
+	defaultMaxTokens = 4096
+)
+
+// Config configures a Client. APIKey defaults to the ANTHROPIC_API_KEY
+// environment variable when left empty.
+type Config struct {
+	provider.Config
+	APIKey  string
+	BaseURL string
+}
+
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+func NewClient(config *Config) (*Client, error) {
+	if config == nil || config.Model == "" {
+		return nil, fmt.Errorf("valid config with model is required")
+	}
+	if config.ToolRegistry == nil {
+		return nil, fmt.Errorf("valid config with a tool registry is required")
+	}
+	if config.MaxToolIterations <= 0 {
+		config.MaxToolIterations = provider.DefaultMaxToolIterations
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is required")
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+var _ provider.ChatCompletionProvider = (*Client)(nil)
+
+func (c *Client) SetToolRegistry(registry *tools.Registry) { c.config.ToolRegistry = registry }
+func (c *Client) GetToolRegistry() *tools.Registry         { return c.config.ToolRegistry }
+func (c *Client) SetModel(model string)                    { c.config.Model = model }
+func (c *Client) GetModel() string                         { return c.config.Model }
+func (c *Client) GetMaxToolIterations() int                { return c.config.MaxToolIterations }
+
+// message, tool, and contentBlock mirror the Messages API's request shape.
+// System prompts travel in a separate top-level field rather than as a
+// message with role "system", so those are pulled out in toRequest.
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+type contentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type tool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema interface{} `json:"input_schema"`
+}
+
+type chatRequest struct {
+	Model     string    `json:"model"`
+	System    string    `json:"system,omitempty"`
+	Messages  []message `json:"messages"`
+	Tools     []tool    `json:"tools,omitempty"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream"`
+}
+
+func toRequest(model string, messages []openrouter.ChatCompletionMessage, defs []openrouter.Tool) chatRequest {
+	req := chatRequest{Model: model, MaxTokens: defaultMaxTokens, Stream: true}
+
+	for _, d := range defs {
+		req.Tools = append(req.Tools, tool{
+			Name:        d.Function.Name,
+			Description: d.Function.Description,
+			InputSchema: d.Function.Parameters,
+		})
+	}
+
+	for _, m := range messages {
+		switch m.Role {
+		case "system":
+			if req.System != "" {
+				req.System += "\n\n"
+			}
+			req.System += m.Content.Text
+		case "tool":
+			req.Messages = append(req.Messages, message{
+				Role: "user",
+				Content: []contentBlock{{
+					Type:      "tool_result",
+					ToolUseID: m.ToolCallID,
+					Content:   m.Content.Text,
+				}},
+			})
+		default:
+			blocks := []contentBlock{}
+			if m.Content.Text != "" {
+				blocks = append(blocks, contentBlock{Type: "text", Text: m.Content.Text})
+			}
+			for _, tc := range m.ToolCalls {
+				blocks = append(blocks, contentBlock{
+					Type:  "tool_use",
+					ID:    tc.ID,
+					Name:  tc.Function.Name,
+					Input: json.RawMessage(tc.Function.Arguments),
+				})
+			}
+			req.Messages = append(req.Messages, message{Role: m.Role, Content: blocks})
+		}
+	}
+
+	return req
+}
+
+// sseEvent is the union of the streaming event payloads we care about;
+// fields irrelevant to a given event type are simply left zero.
+type sseEvent struct {
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// pendingToolUse accumulates a tool_use block's streamed partial_json
+// fragments until content_block_stop, since Anthropic streams tool input
+// a few characters at a time rather than all at once.
+type pendingToolUse struct {
+	id, name string
+	args     strings.Builder
+}
+
+func (c *Client) SendMessages(messages []openrouter.ChatCompletionMessage, handler provider.StreamHandler) error {
+	reqBody := toRequest(c.config.Model, messages, c.config.ToolRegistry.GetDefinitions())
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.BaseURL+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.config.APIKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		err := fmt.Errorf("anthropic returned status %s", resp.Status)
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	go c.readStream(resp, handler)
+	return nil
+}
+
+func (c *Client) readStream(resp *http.Response, handler provider.StreamHandler) {
+	pending := map[int]*pendingToolUse{}
+
+	err := provider.ReadSSELines(resp.Body, func(data string) bool {
+		var event sseEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			log.Error("Failed to decode anthropic stream event", "error", err)
+			return true
+		}
+
+		switch event.Type {
+		case "content_block_start":
+			if event.ContentBlock.Type == "tool_use" {
+				pending[event.Index] = &pendingToolUse{id: event.ContentBlock.ID, name: event.ContentBlock.Name}
+			}
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				handler(provider.StreamEvent{Type: provider.StreamEventChunk, Content: event.Delta.Text})
+			case "input_json_delta":
+				if p, ok := pending[event.Index]; ok {
+					p.args.WriteString(event.Delta.PartialJSON)
+				}
+			}
+		case "content_block_stop":
+			if p, ok := pending[event.Index]; ok {
+				delete(pending, event.Index)
+				handler(provider.StreamEvent{Type: provider.StreamEventToolCall, ToolCalls: []provider.ToolCall{{
+					Index: event.Index,
+					ID:    p.id,
+					Type:  "function",
+					Function: provider.ToolCallFunction{
+						Name:      p.name,
+						Arguments: p.args.String(),
+					},
+				}}})
+			}
+		case "message_delta":
+			if event.Usage.OutputTokens > 0 {
+				handler(provider.StreamEvent{Type: provider.StreamEventUsage, Usage: &openrouter.Usage{
+					CompletionTokens: event.Usage.OutputTokens,
+				}})
+			}
+		case "message_stop":
+			handler(provider.StreamEvent{Type: provider.StreamEventDone})
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+	}
+}
@@ -0,0 +1,185 @@
+// Package gemini implements provider.ChatCompletionProvider against
+// Google's OpenAI-compatible endpoint for the Gemini API. The wire format
+// is the same SSE-framed, "[DONE]"-terminated shape OpenRouter itself
+// proxies, so this mirrors provider/openai's Client closely rather than
+// talking to Gemini's native generateContent API.
+package gemini
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/provider"
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+const defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
+
+// Config configures a Client. APIKey defaults to the GEMINI_API_KEY
+// environment variable when left empty.
+type Config struct {
+	provider.Config
+	APIKey  string
+	BaseURL string
+}
+
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+func NewClient(config *Config) (*Client, error) {
+	if config == nil || config.Model == "" {
+		return nil, fmt.Errorf("valid config with model is required")
+	}
+	if config.ToolRegistry == nil {
+		return nil, fmt.Errorf("valid config with a tool registry is required")
+	}
+	if config.MaxToolIterations <= 0 {
+		config.MaxToolIterations = provider.DefaultMaxToolIterations
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("GEMINI_API_KEY")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY environment variable is required")
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+var _ provider.ChatCompletionProvider = (*Client)(nil)
+
+func (c *Client) SetToolRegistry(registry *tools.Registry) { c.config.ToolRegistry = registry }
+func (c *Client) GetToolRegistry() *tools.Registry         { return c.config.ToolRegistry }
+func (c *Client) SetModel(model string)                    { c.config.Model = model }
+func (c *Client) GetModel() string                         { return c.config.Model }
+func (c *Client) GetMaxToolIterations() int                { return c.config.MaxToolIterations }
+
+type chatRequest struct {
+	Model    string                             `json:"model"`
+	Messages []openrouter.ChatCompletionMessage `json:"messages"`
+	Stream   bool                               `json:"stream"`
+	Tools    []openrouter.Tool                  `json:"tools,omitempty"`
+}
+
+// streamChunk is one SSE "data:" payload from the chat completions stream.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    *int   `json:"index"`
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openrouter.Usage `json:"usage"`
+}
+
+func (c *Client) SendMessages(messages []openrouter.ChatCompletionMessage, handler provider.StreamHandler) error {
+	reqBody := chatRequest{
+		Model:    c.config.Model,
+		Messages: messages,
+		Stream:   true,
+		Tools:    c.config.ToolRegistry.GetDefinitions(),
+	}
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		err := fmt.Errorf("gemini returned status %s", resp.Status)
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	go c.readStream(resp, handler)
+	return nil
+}
+
+func (c *Client) readStream(resp *http.Response, handler provider.StreamHandler) {
+	err := provider.ReadSSELines(resp.Body, func(data string) bool {
+		if data == "[DONE]" {
+			handler(provider.StreamEvent{Type: provider.StreamEventDone})
+			return false
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			log.Error("Failed to decode gemini stream chunk", "error", err)
+			return true
+		}
+
+		if chunk.Usage != nil {
+			handler(provider.StreamEvent{Type: provider.StreamEventUsage, Usage: chunk.Usage})
+		}
+
+		if len(chunk.Choices) == 0 {
+			return true
+		}
+		delta := chunk.Choices[0].Delta
+
+		if delta.Content != "" {
+			handler(provider.StreamEvent{Type: provider.StreamEventChunk, Content: delta.Content})
+		}
+
+		if len(delta.ToolCalls) > 0 {
+			toolCalls := make([]provider.ToolCall, 0, len(delta.ToolCalls))
+			for _, tc := range delta.ToolCalls {
+				index := 0
+				if tc.Index != nil {
+					index = *tc.Index
+				}
+				toolCalls = append(toolCalls, provider.ToolCall{
+					Index: index,
+					ID:    tc.ID,
+					Type:  tc.Type,
+					Function: provider.ToolCallFunction{
+						Name:      tc.Function.Name,
+						Arguments: tc.Function.Arguments,
+					},
+				})
+			}
+			handler(provider.StreamEvent{Type: provider.StreamEventToolCall, ToolCalls: toolCalls})
+		}
+
+		return true
+	})
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+	}
+}
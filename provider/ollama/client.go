@@ -0,0 +1,222 @@
+// Package ollama implements provider.ChatCompletionProvider against a
+// local Ollama server's streaming /api/chat endpoint (NDJSON, one JSON
+// object per line, no SSE framing).
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/festeh/bro/provider"
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+const defaultBaseURL = "http://localhost:11434"
+
+// Config configures a Client. BaseURL defaults to defaultBaseURL, or the
+// OLLAMA_HOST environment variable if set, when left empty.
+type Config struct {
+	provider.Config
+	BaseURL string
+}
+
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewClient builds a Client. Unlike the hosted backends, Ollama needs no
+// API key - it's assumed to be reachable on the local network.
+func NewClient(config *Config) (*Client, error) {
+	if config == nil || config.Model == "" {
+		return nil, fmt.Errorf("valid config with model is required")
+	}
+	if config.ToolRegistry == nil {
+		return nil, fmt.Errorf("valid config with a tool registry is required")
+	}
+	if config.MaxToolIterations <= 0 {
+		config.MaxToolIterations = provider.DefaultMaxToolIterations
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = os.Getenv("OLLAMA_HOST")
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+var _ provider.ChatCompletionProvider = (*Client)(nil)
+
+func (c *Client) SetToolRegistry(registry *tools.Registry) { c.config.ToolRegistry = registry }
+func (c *Client) GetToolRegistry() *tools.Registry         { return c.config.ToolRegistry }
+func (c *Client) SetModel(model string)                    { c.config.Model = model }
+func (c *Client) GetModel() string                         { return c.config.Model }
+func (c *Client) GetMaxToolIterations() int                { return c.config.MaxToolIterations }
+
+// chatMessage and chatTool mirror the shapes Ollama's /api/chat expects;
+// they're deliberately narrower than go-openrouter's types since Ollama
+// only understands a subset (no per-message name/id beyond tool_call_id).
+type chatMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content,omitempty"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+type chatToolCall struct {
+	Function chatToolCallFunction `json:"function"`
+}
+
+type chatToolCallFunction struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type chatTool struct {
+	Type     string                         `json:"type"`
+	Function *openrouter.FunctionDefinition `json:"function"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Tools    []chatTool    `json:"tools,omitempty"`
+}
+
+// chatResponseLine is one NDJSON line from a streaming /api/chat response.
+type chatResponseLine struct {
+	Message struct {
+		Role      string         `json:"role"`
+		Content   string         `json:"content"`
+		ToolCalls []chatToolCall `json:"tool_calls"`
+	} `json:"message"`
+	Done            bool `json:"done"`
+	PromptEvalCount int  `json:"prompt_eval_count"`
+	EvalCount       int  `json:"eval_count"`
+}
+
+func toChatMessages(messages []openrouter.ChatCompletionMessage) []chatMessage {
+	result := make([]chatMessage, 0, len(messages))
+	for _, m := range messages {
+		msg := chatMessage{Role: m.Role, Content: m.Content.Text}
+		for _, tc := range m.ToolCalls {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+				log.Error("Failed to decode tool call arguments for ollama request", "error", err)
+			}
+			msg.ToolCalls = append(msg.ToolCalls, chatToolCall{
+				Function: chatToolCallFunction{Name: tc.Function.Name, Arguments: args},
+			})
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+func toChatTools(defs []openrouter.Tool) []chatTool {
+	result := make([]chatTool, 0, len(defs))
+	for _, d := range defs {
+		result = append(result, chatTool{Type: "function", Function: d.Function})
+	}
+	return result
+}
+
+func (c *Client) SendMessages(messages []openrouter.ChatCompletionMessage, handler provider.StreamHandler) error {
+	reqBody := chatRequest{
+		Model:    c.config.Model,
+		Messages: toChatMessages(messages),
+		Stream:   true,
+		Tools:    toChatTools(c.config.ToolRegistry.GetDefinitions()),
+	}
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.config.BaseURL+"/api/chat", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		err := fmt.Errorf("ollama returned status %s", resp.Status)
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+		return err
+	}
+
+	go c.readNDJSON(resp.Body, handler)
+	return nil
+}
+
+func (c *Client) readNDJSON(body io.ReadCloser, handler provider.StreamHandler) {
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk chatResponseLine
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+			return
+		}
+
+		if chunk.Message.Content != "" {
+			handler(provider.StreamEvent{Type: provider.StreamEventChunk, Content: chunk.Message.Content})
+		}
+
+		if len(chunk.Message.ToolCalls) > 0 {
+			toolCalls := make([]provider.ToolCall, 0, len(chunk.Message.ToolCalls))
+			for i, tc := range chunk.Message.ToolCalls {
+				argsJSON, err := json.Marshal(tc.Function.Arguments)
+				if err != nil {
+					log.Error("Failed to encode tool call arguments from ollama response", "error", err)
+					continue
+				}
+				toolCalls = append(toolCalls, provider.ToolCall{
+					Index: i,
+					Type:  "function",
+					Function: provider.ToolCallFunction{
+						Name:      tc.Function.Name,
+						Arguments: string(argsJSON),
+					},
+				})
+			}
+			handler(provider.StreamEvent{Type: provider.StreamEventToolCall, ToolCalls: toolCalls})
+		}
+
+		if chunk.Done {
+			handler(provider.StreamEvent{
+				Type: provider.StreamEventUsage,
+				Usage: &openrouter.Usage{
+					PromptTokens:     chunk.PromptEvalCount,
+					CompletionTokens: chunk.EvalCount,
+				},
+			})
+			handler(provider.StreamEvent{Type: provider.StreamEventDone})
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		handler(provider.StreamEvent{Type: provider.StreamEventError, Error: err})
+	}
+}
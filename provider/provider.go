@@ -0,0 +1,73 @@
+// Package provider defines the backend-agnostic contract between App and
+// whichever chat-completion vendor it talks to. openrouter.Client was the
+// only implementation for a long time; this interface lets ollama,
+// anthropic, and openai implementations (see the sibling packages) sit
+// alongside it, selected at startup via agents.Agent.Provider.
+package provider
+
+import (
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// StreamEvent, ToolCall, and ToolCallFunction describe streamed completion
+// output in a vendor-neutral shape. Every provider implementation
+// translates its own wire format into these before handing them to a
+// StreamHandler, so App.Update never needs to know which backend is live.
+type StreamEvent struct {
+	Type      string
+	Content   string
+	Error     error
+	ToolCalls []ToolCall
+	Usage     *openrouter.Usage
+}
+
+type ToolCall struct {
+	Index    int              `json:"index"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+const (
+	StreamEventChunk    = "chunk"
+	StreamEventDone     = "done"
+	StreamEventError    = "error"
+	StreamEventToolCall = "tool_call"
+	StreamEventUsage    = "usage"
+)
+
+type StreamHandler func(StreamEvent)
+
+// DefaultMaxToolIterations bounds how many tool-result round trips a
+// single user turn may take when a provider's MaxToolIterations is unset.
+const DefaultMaxToolIterations = 10
+
+// Config is the knob set shared by every backend's NewClient constructor:
+// model name, tool registry, and tool-iteration bound. Backends that need
+// more (an API key, a base URL) embed this alongside their own fields,
+// the same way openrouter.Config predates and mirrors it.
+type Config struct {
+	Model             string
+	ToolRegistry      *tools.Registry
+	MaxToolIterations int
+}
+
+// ChatCompletionProvider is implemented by every backend (openrouter,
+// ollama, anthropic, openai, ...). Each implementation owns its own model
+// name, tool registry, and tool-iteration bound, mirroring how
+// openrouter.Client already managed them - the interface just lets App
+// hold one without caring which vendor it is.
+type ChatCompletionProvider interface {
+	SendMessages(messages []openrouter.ChatCompletionMessage, handler StreamHandler) error
+	GetToolRegistry() *tools.Registry
+	SetToolRegistry(registry *tools.Registry)
+	GetModel() string
+	SetModel(model string)
+	GetMaxToolIterations() int
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ReadSSELines scans an OpenAI/Anthropic-style Server-Sent-Events stream
+// and calls onData with the payload of each "data: ..." line, in order.
+// Blank lines (event separators) and non-data fields (e.g. "event: ...")
+// are skipped; onData stops the scan early by returning false.
+func ReadSSELines(body io.ReadCloser, onData func(data string) bool) error {
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		if !onData(strings.TrimSpace(data)) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,150 @@
+// Package selection decides which files bro's filesystem-traversal tools
+// (filefinder, grep, and any future ones) surface to the agent, so
+// vendored dependencies, build output, and binary blobs don't drown out
+// real source in results. It used to live only inside tools/grep; pulling
+// it out here lets filefinder share the same rules instead of reimplementing
+// them, and lets tools.ToolContext inject one SelectFunc into every
+// filesystem-touching tool at construction time.
+package selection
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SelectFunc decides whether a path should be included in search results.
+// Return false to exclude the path. Modeled on restic's archiver
+// SelectFilter pattern: func(item string, fi os.FileInfo) bool. fi may be
+// nil when the caller hasn't stat'd the path, in which case the binary
+// content sniff is skipped.
+type SelectFunc func(path string, fi os.FileInfo) bool
+
+const IgnoreFileName = ".broignore"
+
+// defaultExcludes are skipped at every root regardless of .broignore or
+// .gitignore content - generated or vendored directories that are almost
+// never useful to the agent and are often huge.
+var defaultExcludes = []string{".git", "node_modules", "vendor", "target", "__pycache__"}
+
+// Config holds excludes beyond defaultExcludes, tunable per-user via
+// ~/.bro/ignore.txt (see LoadConfig) instead of editing code.
+type Config struct {
+	ExtraExcludes []string
+}
+
+// DefaultConfig returns a Config with no extra excludes: just
+// defaultExcludes plus whatever .broignore/.gitignore say at each root.
+func DefaultConfig() *Config {
+	return &Config{}
+}
+
+// LoadConfig reads ~/.bro/ignore.txt, the same directory History, Session,
+// and Stats keep their own files in.
+func LoadConfig() (*Config, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfigFromFile(filepath.Join(homeDir, ".bro", "ignore.txt"))
+}
+
+// LoadConfigFromFile reads a selection config from an explicit path; tests
+// use this to avoid touching the real ~/.bro. A missing file is not an
+// error - it just means no extra excludes.
+func LoadConfigFromFile(path string) (*Config, error) {
+	return &Config{ExtraExcludes: readPatternFile(path)}, nil
+}
+
+// New builds a SelectFunc for root: defaultExcludes and cfg's
+// ExtraExcludes always apply; .broignore/.gitignore patterns at root apply
+// unless includeIgnored is true (the tool argument of the same name passes
+// this straight through). It also returns the combined raw pattern list so
+// callers can translate it into e.g. ripgrep --glob exclusions.
+func New(root string, cfg *Config, includeIgnored bool) (SelectFunc, []string) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	var patterns []string
+	patterns = append(patterns, defaultExcludes...)
+	patterns = append(patterns, cfg.ExtraExcludes...)
+
+	if !includeIgnored {
+		patterns = append(patterns, readPatternFile(filepath.Join(root, IgnoreFileName))...)
+		patterns = append(patterns, readPatternFile(filepath.Join(root, ".gitignore"))...)
+	}
+
+	return func(path string, fi os.FileInfo) bool {
+		if !matchPatterns(patterns, path) {
+			return false
+		}
+		if fi != nil && !fi.IsDir() && IsBinary(path) {
+			return false
+		}
+		return true
+	}, patterns
+}
+
+func matchPatterns(patterns []string, path string) bool {
+	base := filepath.Base(path)
+	included := true
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		pattern := strings.TrimPrefix(p, "!")
+
+		matched, _ := filepath.Match(pattern, base)
+		if !matched {
+			matched, _ = filepath.Match(pattern, path)
+		}
+		if matched {
+			included = negate
+		}
+	}
+	return included
+}
+
+func readPatternFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// sniffSize is how many leading bytes IsBinary reads - matches the
+// heuristic git itself uses to classify files as binary.
+const sniffSize = 8000
+
+// IsBinary reports whether path's leading bytes contain a NUL byte, the
+// same heuristic git uses to decide whether to treat a file as binary. A
+// file that can't be opened is reported as not binary so it doesn't get
+// silently dropped from results.
+func IsBinary(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffSize)
+	n, _ := f.Read(buf)
+	for _, b := range buf[:n] {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
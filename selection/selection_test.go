@@ -0,0 +1,122 @@
+package selection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewAppliesIgnoreFilePatterns(t *testing.T) {
+	tempDir := t.TempDir()
+
+	ignoreContent := "*.log\nbuild/\n!keep.log\n"
+	if err := os.WriteFile(filepath.Join(tempDir, IgnoreFileName), []byte(ignoreContent), 0644); err != nil {
+		t.Fatalf("Failed to write .broignore: %v", err)
+	}
+
+	filter, patterns := New(tempDir, nil, false)
+
+	if len(patterns) != len(defaultExcludes)+3 {
+		t.Fatalf("Expected %d patterns (defaults + 3 from .broignore), got %d: %v", len(defaultExcludes)+3, len(patterns), patterns)
+	}
+
+	if filter("debug.log", nil) {
+		t.Error("Expected debug.log to be excluded")
+	}
+	if !filter("keep.log", nil) {
+		t.Error("Expected keep.log to be re-included by negated pattern")
+	}
+	if !filter("main.go", nil) {
+		t.Error("Expected main.go to be included")
+	}
+}
+
+func TestNewAppliesDefaultExcludesEvenWithoutIgnoreFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	filter, patterns := New(tempDir, nil, false)
+
+	if len(patterns) != len(defaultExcludes) {
+		t.Errorf("Expected only the default excludes, got %v", patterns)
+	}
+	if filter("node_modules", nil) {
+		t.Error("Expected node_modules to be excluded by defaults")
+	}
+	if !filter("main.go", nil) {
+		t.Error("Expected main.go to be included")
+	}
+}
+
+func TestNewIncludeIgnoredSkipsIgnoreFileButNotDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, IgnoreFileName), []byte("*.log\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .broignore: %v", err)
+	}
+
+	filter, _ := New(tempDir, nil, true)
+
+	if !filter("debug.log", nil) {
+		t.Error("Expected includeIgnored to bypass .broignore")
+	}
+	if filter("vendor", nil) {
+		t.Error("Expected includeIgnored to still honor the hardcoded default excludes")
+	}
+}
+
+func TestNewAppliesConfigExtraExcludes(t *testing.T) {
+	filter, patterns := New(t.TempDir(), &Config{ExtraExcludes: []string{"*.generated.go"}}, false)
+
+	if len(patterns) != len(defaultExcludes)+1 {
+		t.Fatalf("Expected defaults + 1 extra exclude, got %v", patterns)
+	}
+	if filter("api.generated.go", nil) {
+		t.Error("Expected api.generated.go to be excluded by the extra exclude pattern")
+	}
+}
+
+func TestLoadConfigFromFile(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "ignore.txt")
+	if err := os.WriteFile(path, []byte("*.bin\n# a comment\n\ndist/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	if len(cfg.ExtraExcludes) != 2 {
+		t.Fatalf("Expected 2 excludes, got %v", cfg.ExtraExcludes)
+	}
+}
+
+func TestLoadConfigFromFileMissing(t *testing.T) {
+	cfg, err := LoadConfigFromFile(filepath.Join(t.TempDir(), "does-not-exist.txt"))
+	if err != nil {
+		t.Fatalf("Expected a missing config file to not be an error, got %v", err)
+	}
+	if len(cfg.ExtraExcludes) != 0 {
+		t.Errorf("Expected no excludes, got %v", cfg.ExtraExcludes)
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tempDir := t.TempDir()
+
+	textPath := filepath.Join(tempDir, "text.txt")
+	if err := os.WriteFile(textPath, []byte("hello, world\n"), 0644); err != nil {
+		t.Fatalf("Failed to write text file: %v", err)
+	}
+	if IsBinary(textPath) {
+		t.Error("Expected a plain text file to not be detected as binary")
+	}
+
+	binPath := filepath.Join(tempDir, "blob.bin")
+	if err := os.WriteFile(binPath, []byte{0x50, 0x4b, 0x00, 0x00, 0x03, 0x04}, 0644); err != nil {
+		t.Fatalf("Failed to write binary file: %v", err)
+	}
+	if !IsBinary(binPath) {
+		t.Error("Expected a file containing a NUL byte to be detected as binary")
+	}
+}
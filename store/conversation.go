@@ -0,0 +1,284 @@
+package store
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned by lookups for a conversation or message id that
+// doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Conversation is a named thread of messages. CurrentLeafID tracks which
+// message is the tip of the branch `view`/`reply` act on; editing an
+// earlier message moves it by creating a sibling rather than overwriting
+// history (see ForkFrom).
+type Conversation struct {
+	ID            string
+	Title         string
+	CreatedAt     time.Time
+	CurrentLeafID int64 // 0 means the conversation has no messages yet
+}
+
+// Message is one turn in a conversation. ParentID is 0 for the first
+// message; every other message hangs off the message it replied to or
+// forked from, which is what makes branching possible - two messages can
+// share a ParentID without either overwriting the other.
+type Message struct {
+	ID             int64
+	ConversationID string
+	ParentID       int64
+	Role           string
+	Content        string
+	CreatedAt      time.Time
+}
+
+// NewConversation starts a conversation with prompt as its first (role
+// "user") message and returns both.
+func (s *Store) NewConversation(prompt string) (*Conversation, *Message, error) {
+	id, err := randomID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conv := &Conversation{ID: id, Title: title(prompt), CreatedAt: time.Now()}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO conversations (id, title, created_at) VALUES (?, ?, ?)`,
+		conv.ID, conv.Title, conv.CreatedAt.Format(time.RFC3339),
+	); err != nil {
+		return nil, nil, fmt.Errorf("creating conversation: %w", err)
+	}
+
+	msg, err := insertMessage(tx, conv.ID, 0, "user", prompt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE conversations SET current_leaf_id = ? WHERE id = ?`, msg.ID, conv.ID); err != nil {
+		return nil, nil, fmt.Errorf("updating leaf: %w", err)
+	}
+	conv.CurrentLeafID = msg.ID
+
+	return conv, msg, tx.Commit()
+}
+
+// Reply appends content (role "assistant" or "user") to conversationID's
+// current leaf and advances the leaf to the new message.
+func (s *Store) Reply(conversationID, role, content string) (*Message, error) {
+	conv, err := s.Get(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.appendMessage(conv, conv.CurrentLeafID, role, content)
+}
+
+// ForkFrom appends content as a sibling of fromMessageID (same parent),
+// then makes it the conversation's new current leaf. This is how editing
+// a prior user message works: the original message and its replies stay
+// in the database untouched, and the conversation's active branch now
+// runs through the edited version instead.
+func (s *Store) ForkFrom(conversationID string, fromMessageID int64, role, content string) (*Message, error) {
+	conv, err := s.Get(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID sql.NullInt64
+	if err := s.db.QueryRow(`SELECT parent_id FROM messages WHERE id = ? AND conversation_id = ?`, fromMessageID, conversationID).Scan(&parentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("looking up fork point: %w", err)
+	}
+
+	return s.appendMessage(conv, parentID.Int64, role, content)
+}
+
+func (s *Store) appendMessage(conv *Conversation, parentID int64, role, content string) (*Message, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	msg, err := insertMessage(tx, conv.ID, parentID, role, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE conversations SET current_leaf_id = ? WHERE id = ?`, msg.ID, conv.ID); err != nil {
+		return nil, fmt.Errorf("updating leaf: %w", err)
+	}
+
+	return msg, tx.Commit()
+}
+
+func insertMessage(tx *sql.Tx, conversationID string, parentID int64, role, content string) (*Message, error) {
+	msg := &Message{ConversationID: conversationID, ParentID: parentID, Role: role, Content: content, CreatedAt: time.Now()}
+
+	var parent sql.NullInt64
+	if parentID != 0 {
+		parent = sql.NullInt64{Int64: parentID, Valid: true}
+	}
+
+	res, err := tx.Exec(
+		`INSERT INTO messages (conversation_id, parent_id, role, content, created_at) VALUES (?, ?, ?, ?, ?)`,
+		conversationID, parent, role, content, msg.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inserting message: %w", err)
+	}
+	msg.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Get returns the conversation with id.
+func (s *Store) Get(id string) (*Conversation, error) {
+	var conv Conversation
+	var createdAt string
+	var leaf sql.NullInt64
+
+	err := s.db.QueryRow(
+		`SELECT id, title, created_at, current_leaf_id FROM conversations WHERE id = ?`, id,
+	).Scan(&conv.ID, &conv.Title, &createdAt, &leaf)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading conversation: %w", err)
+	}
+
+	conv.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	conv.CurrentLeafID = leaf.Int64
+
+	return &conv, nil
+}
+
+// Branch returns the messages on conversationID's current active branch,
+// root first - i.e. the path from the first message down to CurrentLeafID.
+func (s *Store) Branch(conversationID string) ([]Message, error) {
+	conv, err := s.Get(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.CurrentLeafID == 0 {
+		return nil, nil
+	}
+
+	var messages []Message
+	nextID := conv.CurrentLeafID
+	for nextID != 0 {
+		var msg Message
+		var createdAt string
+		var parent sql.NullInt64
+		err := s.db.QueryRow(
+			`SELECT id, conversation_id, parent_id, role, content, created_at FROM messages WHERE id = ?`, nextID,
+		).Scan(&msg.ID, &msg.ConversationID, &parent, &msg.Role, &msg.Content, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("loading message %d: %w", nextID, err)
+		}
+		msg.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, err
+		}
+		msg.ParentID = parent.Int64
+		messages = append(messages, msg)
+		nextID = parent.Int64
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// List returns every conversation, most recently created first.
+func (s *Store) List() ([]Conversation, error) {
+	rows, err := s.db.Query(`SELECT id, title, created_at, current_leaf_id FROM conversations ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversations []Conversation
+	for rows.Next() {
+		var conv Conversation
+		var createdAt string
+		var leaf sql.NullInt64
+		if err := rows.Scan(&conv.ID, &conv.Title, &createdAt, &leaf); err != nil {
+			return nil, err
+		}
+		conv.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, err
+		}
+		conv.CurrentLeafID = leaf.Int64
+		conversations = append(conversations, conv)
+	}
+	return conversations, rows.Err()
+}
+
+// Delete removes conversationID and every message in it.
+func (s *Store) Delete(conversationID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM messages WHERE conversation_id = ?`, conversationID); err != nil {
+		return err
+	}
+	res, err := tx.Exec(`DELETE FROM conversations WHERE id = ?`, conversationID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating conversation id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// title derives a conversation's display title from its first prompt:
+// the first line, capped so `bro ls` stays one row per conversation.
+func title(prompt string) string {
+	const maxLen = 60
+	for i, r := range prompt {
+		if r == '\n' {
+			prompt = prompt[:i]
+			break
+		}
+	}
+	if len(prompt) > maxLen {
+		return prompt[:maxLen] + "..."
+	}
+	return prompt
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestNewConversationAndBranch(t *testing.T) {
+	s := openTestStore(t)
+
+	conv, root, err := s.NewConversation("hello there")
+	if err != nil {
+		t.Fatalf("NewConversation failed: %v", err)
+	}
+	if conv.Title != "hello there" {
+		t.Errorf("Expected title 'hello there', got %q", conv.Title)
+	}
+	if root.Role != "user" || root.ParentID != 0 {
+		t.Errorf("Expected root message to be a parentless user message, got %+v", root)
+	}
+
+	reply, err := s.Reply(conv.ID, "assistant", "hi!")
+	if err != nil {
+		t.Fatalf("Reply failed: %v", err)
+	}
+	if reply.ParentID != root.ID {
+		t.Errorf("Expected reply's parent to be root (%d), got %d", root.ID, reply.ParentID)
+	}
+
+	branch, err := s.Branch(conv.ID)
+	if err != nil {
+		t.Fatalf("Branch failed: %v", err)
+	}
+	if len(branch) != 2 || branch[0].ID != root.ID || branch[1].ID != reply.ID {
+		t.Fatalf("Expected branch [root, reply], got %+v", branch)
+	}
+}
+
+func TestForkFromCreatesSiblingBranch(t *testing.T) {
+	s := openTestStore(t)
+
+	conv, root, err := s.NewConversation("what's the weather")
+	if err != nil {
+		t.Fatalf("NewConversation failed: %v", err)
+	}
+	reply, err := s.Reply(conv.ID, "assistant", "sunny")
+	if err != nil {
+		t.Fatalf("Reply failed: %v", err)
+	}
+
+	forked, err := s.ForkFrom(conv.ID, root.ID, "user", "what's the weather in Paris")
+	if err != nil {
+		t.Fatalf("ForkFrom failed: %v", err)
+	}
+	if forked.ParentID != 0 {
+		t.Errorf("Expected forked message to share root's parent (0), got %d", forked.ParentID)
+	}
+	if forked.ID == reply.ID {
+		t.Error("Expected ForkFrom to create a new message, not reuse the old reply")
+	}
+
+	branch, err := s.Branch(conv.ID)
+	if err != nil {
+		t.Fatalf("Branch failed: %v", err)
+	}
+	if len(branch) != 1 || branch[0].ID != forked.ID {
+		t.Fatalf("Expected the active branch to now be just the forked root, got %+v", branch)
+	}
+
+	// The original reply is still in the database, just no longer on the
+	// active branch - editing never destroys history.
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE conversation_id = ?`, conv.ID).Scan(&count); err != nil {
+		t.Fatalf("count query failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 messages total (root, original reply, fork), got %d", count)
+	}
+}
+
+func TestListAndDelete(t *testing.T) {
+	s := openTestStore(t)
+
+	convA, _, err := s.NewConversation("first")
+	if err != nil {
+		t.Fatalf("NewConversation failed: %v", err)
+	}
+	if _, _, err := s.NewConversation("second"); err != nil {
+		t.Fatalf("NewConversation failed: %v", err)
+	}
+
+	conversations, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(conversations) != 2 {
+		t.Fatalf("Expected 2 conversations, got %d", len(conversations))
+	}
+
+	if err := s.Delete(convA.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := s.Get(convA.ID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+
+	conversations, err = s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(conversations) != 1 {
+		t.Fatalf("Expected 1 conversation after delete, got %d", len(conversations))
+	}
+}
+
+func TestGetUnknownConversation(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.Get("does-not-exist"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
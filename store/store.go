@@ -0,0 +1,77 @@
+// Package store persists conversations to SQLite so `bro` keeps a history
+// across runs instead of losing it the moment the TUI exits. It uses
+// modernc.org/sqlite (a pure-Go driver) rather than mattn/go-sqlite3, to
+// avoid requiring CGO to build bro.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS conversations (
+	id              TEXT PRIMARY KEY,
+	title           TEXT NOT NULL,
+	created_at      TEXT NOT NULL,
+	current_leaf_id INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	conversation_id TEXT NOT NULL,
+	parent_id       INTEGER,
+	role            TEXT NOT NULL,
+	content         TEXT NOT NULL,
+	created_at      TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id ON messages(conversation_id);
+`
+
+// Store is a handle to the conversation database. It's safe for
+// concurrent use, same as the *sql.DB it wraps.
+type Store struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the db path bro uses when no path is given
+// explicitly: ~/.bro/bro.db, alongside config.yaml and the session/history
+// files config.InitializeBroDirectory already keeps there.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".bro", "bro.db"), nil
+}
+
+// Open creates the database at path if it doesn't exist yet and applies
+// the schema, which is safe to re-run on every start (CREATE TABLE/INDEX
+// IF NOT EXISTS).
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating db directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening db: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("applying schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,181 @@
+// Package support builds the redacted diagnostic bundle behind
+// `bro support dump`: the current log.txt, ~/.bro's config/history/session
+// files, a stats snapshot, and the runtime/model/tool registry info that
+// would otherwise get scraped together by hand into a GitHub issue.
+package support
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/festeh/bro/agents"
+	"github.com/festeh/bro/app"
+	"github.com/festeh/bro/config"
+)
+
+// MaxSessions bounds how many of the most recent session logs Dump folds
+// into the bundle, so a long-lived ~/.bro doesn't turn every dump into a
+// multi-megabyte tarball.
+const MaxSessions = 5
+
+// registrySnapshot is the "OpenRouter model/tool registry snapshot" the
+// request asked for: the configured model aliases and the tool names
+// agents.BuildToolRegistry gives the agent (appConfig.Tools is not
+// applied - see buildRegistrySnapshot).
+type registrySnapshot struct {
+	Models []string `json:"models"`
+	Tools  []string `json:"tools"`
+}
+
+// Dump writes a gzip-compressed tar archive to w: log.txt, ~/.bro's
+// config.yaml and history.rec, the last MaxSessions session logs, a
+// snapshot of today's config.Stats, the dynamic runtime preamble from
+// app.GenerateSystemPrompt, and agent's resolved model/tool registry.
+// Every file is redacted against apiKeys and Redact's secret patterns
+// before it's written - pass every credential the active backends might
+// have used (OpenRouter's plus whichever of ANTHROPIC_API_KEY/
+// GEMINI_API_KEY/OPENAI_API_KEY are set), not just OpenRouter's. Optional
+// files that don't exist yet (no log.txt, no sessions) are skipped rather
+// than failing the dump.
+func Dump(w io.Writer, appConfig *config.Config, agent agents.Agent, apiKeys ...string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := addOptionalFile(tw, "log.txt", "log.txt", apiKeys); err != nil {
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("resolving home directory: %w", err)
+	}
+	broDir := filepath.Join(homeDir, ".bro")
+
+	if err := addOptionalFile(tw, filepath.Join(broDir, config.ModelsConfigFile), config.ModelsConfigFile, apiKeys); err != nil {
+		return err
+	}
+	if err := addOptionalFile(tw, filepath.Join(broDir, config.HISTORY_FILE), config.HISTORY_FILE, apiKeys); err != nil {
+		return err
+	}
+	if err := addRecentSessions(tw, apiKeys); err != nil {
+		return err
+	}
+
+	if appConfig != nil && appConfig.Stats != nil {
+		if err := addJSON(tw, "stats.json", appConfig.Stats.GetTodaysStats(), apiKeys); err != nil {
+			return err
+		}
+	}
+
+	if err := addBytes(tw, "runtime.txt", []byte(app.GenerateSystemPrompt()), apiKeys); err != nil {
+		return err
+	}
+
+	if appConfig != nil {
+		if err := addJSON(tw, "registry.json", buildRegistrySnapshot(*appConfig, agent), apiKeys); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing tar writer: %w", err)
+	}
+	return gz.Close()
+}
+
+// buildRegistrySnapshot resolves agent's toolbox the same way
+// app.NewProvider does (agents.BuildToolRegistry plus appConfig.Output),
+// without appConfig.Tools/ModelEntry overrides - the snapshot describes
+// what's configured, not one particular request's narrowed view of it.
+func buildRegistrySnapshot(appConfig config.Config, agent agents.Agent) registrySnapshot {
+	registry := agents.BuildToolRegistry(agent, appConfig.Output)
+
+	toolNames := make([]string, 0, len(registry.GetAll()))
+	for _, tool := range registry.GetAll() {
+		toolNames = append(toolNames, tool.Name())
+	}
+	sort.Strings(toolNames)
+
+	return registrySnapshot{
+		Models: appConfig.AvailableModels,
+		Tools:  toolNames,
+	}
+}
+
+// addBytes redacts data and writes it into tw under name.
+func addBytes(tw *tar.Writer, name string, data []byte, apiKeys []string) error {
+	data = Redact(data, apiKeys...)
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+		return fmt.Errorf("writing %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// addOptionalFile reads path and writes it into tw under arcName,
+// silently skipping a file that doesn't exist.
+func addOptionalFile(tw *tar.Writer, path, arcName string, apiKeys []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return addBytes(tw, arcName, data, apiKeys)
+}
+
+// addJSON marshals v and writes it into tw under name.
+func addJSON(tw *tar.Writer, name string, v interface{}, apiKeys []string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", name, err)
+	}
+	return addBytes(tw, name, data, apiKeys)
+}
+
+// addRecentSessions folds the last MaxSessions session logs across every
+// day directory (see config.ListSessionDays/ListSessionFiles) into
+// tw, under sessions/<day>/<HH_MM.jsonl>.
+func addRecentSessions(tw *tar.Writer, apiKeys []string) error {
+	days, err := config.ListSessionDays()
+	if err != nil {
+		return fmt.Errorf("listing session days: %w", err)
+	}
+
+	type sessionFile struct {
+		day  string
+		path string
+	}
+	var all []sessionFile
+	for _, day := range days {
+		files, err := config.ListSessionFiles(day)
+		if err != nil {
+			return fmt.Errorf("listing sessions for %s: %w", day, err)
+		}
+		for _, f := range files {
+			all = append(all, sessionFile{day, f})
+		}
+	}
+
+	if len(all) > MaxSessions {
+		all = all[len(all)-MaxSessions:]
+	}
+
+	for _, sf := range all {
+		arcName := filepath.Join("sessions", sf.day, filepath.Base(sf.path))
+		if err := addOptionalFile(tw, sf.path, arcName, apiKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package support
+
+import "regexp"
+
+// secretPatterns matches the common token shapes that tend to leak into
+// logs and session transcripts: OpenRouter/OpenAI-style "sk-..." keys,
+// Anthropic's "sk-ant-..." keys, and bearer/basic auth headers. Applied on
+// top of the literal OPENROUTER_API_KEY substitution in Redact, since a
+// key can also show up quoted inside a tool call's arguments or result.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{10,}`),
+	regexp.MustCompile(`(?i)(bearer|basic)\s+[A-Za-z0-9._-]{10,}`),
+}
+
+const redactedPlaceholder = "[REDACTED]"
+
+// Redact replaces every non-empty key in apiKeys (OpenRouter's plus
+// whichever of ANTHROPIC_API_KEY/GEMINI_API_KEY/OPENAI_API_KEY the active
+// backend needed) and anything matching secretPatterns with a fixed
+// placeholder, so a diagnostic bundle can be attached to a public GitHub
+// issue without also leaking credentials.
+func Redact(data []byte, apiKeys ...string) []byte {
+	text := string(data)
+
+	for _, apiKey := range apiKeys {
+		if apiKey != "" {
+			text = regexp.MustCompile(regexp.QuoteMeta(apiKey)).ReplaceAllString(text, redactedPlaceholder)
+		}
+	}
+
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, redactedPlaceholder)
+	}
+
+	return []byte(text)
+}
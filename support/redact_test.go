@@ -0,0 +1,42 @@
+package support
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactReplacesLiteralAPIKey(t *testing.T) {
+	out := Redact([]byte("key: abcd1234, ok"), "abcd1234")
+	if strings.Contains(string(out), "abcd1234") {
+		t.Errorf("expected literal API key to be redacted, got %q", out)
+	}
+}
+
+func TestRedactReplacesSecretPatterns(t *testing.T) {
+	cases := []string{
+		"token=sk-abcdefghijklmnop",
+		"Authorization: Bearer abcdefghij1234",
+	}
+	for _, in := range cases {
+		out := string(Redact([]byte(in), ""))
+		if strings.Contains(out, "abcdefghij") {
+			t.Errorf("expected secret pattern in %q to be redacted, got %q", in, out)
+		}
+	}
+}
+
+func TestRedactReplacesMultipleAPIKeys(t *testing.T) {
+	in := "openrouter=or-key-1, anthropic=sk-ant-key-2"
+	out := string(Redact([]byte(in), "or-key-1", "sk-ant-key-2"))
+	if strings.Contains(out, "or-key-1") || strings.Contains(out, "sk-ant-key-2") {
+		t.Errorf("expected both API keys to be redacted, got %q", out)
+	}
+}
+
+func TestRedactLeavesUnrelatedTextAlone(t *testing.T) {
+	in := "nothing secret here"
+	out := string(Redact([]byte(in), "unused-key"))
+	if out != in {
+		t.Errorf("expected unrelated text to pass through unchanged, got %q", out)
+	}
+}
@@ -1,11 +1,15 @@
 package bash
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 
+	"github.com/festeh/bro/tools"
 	"github.com/revrost/go-openrouter"
 )
 
@@ -22,11 +26,22 @@ type Result struct {
 }
 
 // Tool represents the bash tool implementation
-type Tool struct{}
+type Tool struct {
+	// ctx carries the OutputPolicy/OutputCache used to bound a command's
+	// output (see tools.TruncateResult), injected at construction time.
+	ctx tools.ToolContext
+}
 
-// NewTool creates a new bash tool instance
+// NewTool creates a new bash tool instance using the default ToolContext
+// (DefaultOutputPolicy, a fresh OutputCache).
 func NewTool() *Tool {
-	return &Tool{}
+	return NewToolWithContext(tools.DefaultToolContext())
+}
+
+// NewToolWithContext creates a bash tool instance that bounds its output
+// with ctx's OutputPolicy/OutputCache instead of the default ones.
+func NewToolWithContext(ctx tools.ToolContext) *Tool {
+	return &Tool{ctx: ctx}
 }
 
 // Name returns the tool name
@@ -39,16 +54,71 @@ func (t *Tool) Description() string {
 	return GetDescription()
 }
 
-// Execute runs the bash command with the given arguments
-func (t *Tool) Execute(args json.RawMessage) (string, error) {
+// Execute runs the bash command with the given arguments, streaming each
+// stdout/stderr line to progress as the command produces it instead of
+// blocking until it exits - so a long-running command shows up as activity
+// rather than a silent hang. ctx is passed straight to exec.CommandContext,
+// so canceling it (e.g. the user interrupting) kills the command.
+func (t *Tool) Execute(ctx context.Context, args json.RawMessage, progress tools.ProgressReporter) (string, error) {
 	var bashArgs Args
 	if err := json.Unmarshal(args, &bashArgs); err != nil {
 		return "", err
 	}
+	if progress == nil {
+		progress = tools.NoopProgress
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", bashArgs.Command)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Sprintf("Execution error: %s", err.Error()), nil
+	}
 
-	cmd := exec.Command("bash", "-c", bashArgs.Command)
+	var stdout, stderr strings.Builder
+	var mu sync.Mutex
+	lines, bytes := 0, 0
+	report := func(line string) {
+		mu.Lock()
+		lines++
+		bytes += len(line)
+		progress.Report(tools.ProgressUpdate{Line: line, Lines: lines, Bytes: bytes})
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stdoutPipe)
+		for scanner.Scan() {
+			line := scanner.Text()
+			stdout.WriteString(line)
+			stdout.WriteString("\n")
+			report(line)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderrPipe)
+		for scanner.Scan() {
+			line := scanner.Text()
+			stderr.WriteString(line)
+			stderr.WriteString("\n")
+			report(line)
+		}
+	}()
+	wg.Wait()
 
-	stdout, err := cmd.Output()
+	err = cmd.Wait()
 
 	// Build assistant message response
 	var message strings.Builder
@@ -56,25 +126,25 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 	if exitError, ok := err.(*exec.ExitError); ok {
 		// Command failed with non-zero exit code
 		message.WriteString(fmt.Sprintf("Command failed with exit code %d:\n", exitError.ExitCode()))
-		if stderr := string(exitError.Stderr); stderr != "" {
-			message.WriteString(fmt.Sprintf("Error: %s\n", stderr))
+		if stderrStr := stderr.String(); stderrStr != "" {
+			message.WriteString(fmt.Sprintf("Error: %s\n", stderrStr))
 		}
-		if stdout := string(stdout); stdout != "" {
-			message.WriteString(fmt.Sprintf("Output: %s\n", stdout))
+		if stdoutStr := stdout.String(); stdoutStr != "" {
+			message.WriteString(fmt.Sprintf("Output: %s\n", stdoutStr))
 		}
 	} else if err != nil {
-		// Execution error
+		// Execution error (includes context cancellation)
 		message.WriteString(fmt.Sprintf("Execution error: %s", err.Error()))
 	} else {
 		// Success
-		if output := string(stdout); output != "" {
+		if output := stdout.String(); output != "" {
 			message.WriteString(strings.TrimSpace(output))
 		} else {
 			message.WriteString("Command completed successfully (no output)")
 		}
 	}
 
-	return message.String(), nil
+	return tools.TruncateResult(message.String(), t.ctx.OutputPolicy, t.ctx.OutputCache), nil
 }
 
 // GetDefinition returns the OpenRouter tool definition
@@ -0,0 +1,28 @@
+package dirtree
+
+func GetDescription() string {
+	return `Return a JSON tree view of a directory's contents, for orienting yourself in a repository without shelling out to find/tree.
+
+Use this tool when you need to:
+- Get an overview of a project's structure before diving into specific files
+- Find out what's inside a directory without listing file contents
+- Check whether a directory/file exists and what else lives alongside it
+
+The tool will:
+- Return each entry's name and type ("file" or "dir")
+- Expand directories up to depth levels below the given path; deeper directories are summarized as {name, type: "dir", file_count, dir_count} instead of listed
+- Skip dotfiles and common noise directories (.git, node_modules, __pycache__) unless include_hidden is set
+- Respect a .gitignore at the root path, if present
+- Cap the total number of entries at 2000 and set "truncated": true on the result if it hit that cap
+
+Important considerations:
+- Use an absolute path
+- depth defaults to 0 (only the given directory's direct children are listed, with subdirectories summarized); max is 5
+- A deep tree can still be large even summarized - prefer a smaller depth first, then drill into a specific subdirectory with its own call
+
+Examples of good use cases:
+- dir_tree: {"path": "/home/user/project"}
+- dir_tree: {"path": "/home/user/project/src", "depth": 2}
+- dir_tree: {"path": "/home/user/project", "include_hidden": true}
+`
+}
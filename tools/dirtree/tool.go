@@ -0,0 +1,141 @@
+package dirtree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+const (
+	defaultDepth    = 0
+	maxAllowedDepth = 5
+	maxNodes        = 2000
+)
+
+type Args struct {
+	Path          string `json:"path"`
+	Depth         int    `json:"depth,omitempty"`
+	IncludeHidden bool   `json:"include_hidden,omitempty"`
+}
+
+// Node describes one file or directory entry. A directory within the
+// requested depth has Children populated; one beyond it is summarized
+// with FileCount/DirCount instead of being expanded further.
+type Node struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"` // "file" or "dir"
+	Children  []Node `json:"children,omitempty"`
+	FileCount int    `json:"file_count,omitempty"`
+	DirCount  int    `json:"dir_count,omitempty"`
+}
+
+type Result struct {
+	Path      string `json:"path"`
+	Tree      Node   `json:"tree"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Tool represents the dir_tree tool implementation
+type Tool struct{}
+
+// NewTool creates a new dir_tree tool instance
+func NewTool() *Tool {
+	return &Tool{}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "dir_tree"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return GetDescription()
+}
+
+// Execute builds a JSON tree view of the directory at args.Path. It
+// completes fast enough not to need progress reporting or cancellation.
+func (t *Tool) Execute(_ context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var treeArgs Args
+	if err := json.Unmarshal(args, &treeArgs); err != nil {
+		return "", err
+	}
+
+	if treeArgs.Path == "" {
+		return "Error: path is required", nil
+	}
+	if !filepath.IsAbs(treeArgs.Path) {
+		return fmt.Sprintf("Error: path must be absolute, got '%s'", treeArgs.Path), nil
+	}
+	if treeArgs.Depth < 0 || treeArgs.Depth > maxAllowedDepth {
+		return fmt.Sprintf("Error: depth must be between 0 and %d, got %d", maxAllowedDepth, treeArgs.Depth), nil
+	}
+
+	info, err := os.Stat(treeArgs.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Sprintf("Error: path '%s' does not exist", treeArgs.Path), nil
+		}
+		if os.IsPermission(err) {
+			return fmt.Sprintf("Error: permission denied accessing path '%s'", treeArgs.Path), nil
+		}
+		return fmt.Sprintf("Error accessing path '%s': %s", treeArgs.Path, err.Error()), nil
+	}
+	if !info.IsDir() {
+		return fmt.Sprintf("Error: '%s' is not a directory", treeArgs.Path), nil
+	}
+
+	w := &walker{
+		maxDepth:       treeArgs.Depth,
+		includeHidden:  treeArgs.IncludeHidden,
+		maxNodes:       maxNodes,
+		ignorePatterns: readGitignore(treeArgs.Path),
+	}
+
+	result := Result{
+		Path:      treeArgs.Path,
+		Tree:      w.build(treeArgs.Path),
+		Truncated: w.truncated,
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetDefinition returns the OpenRouter tool definition
+func (t *Tool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path to the directory to explore (e.g., /home/user/project)",
+					},
+					"depth": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("How many levels of subdirectories to expand (0-%d, default %d). Directories beyond this depth are summarized with file/dir counts instead of listed.", maxAllowedDepth, defaultDepth),
+					},
+					"include_hidden": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include dotfiles and common noise directories (.git, node_modules, __pycache__) that are skipped by default",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
+	}
+}
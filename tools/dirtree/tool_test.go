@@ -0,0 +1,231 @@
+package dirtree
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/festeh/bro/tools"
+)
+
+func TestDirTreeTool(t *testing.T) {
+	tool := NewTool()
+
+	tempDir, err := os.MkdirTemp("", "dirtree_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	mustWrite := func(relPath, content string) {
+		full := filepath.Join(tempDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", relPath, err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", relPath, err)
+		}
+	}
+
+	mustWrite("README.md", "hello")
+	mustWrite("src/main.go", "package main")
+	mustWrite("src/nested/deep.go", "package nested")
+	mustWrite(".git/HEAD", "ref: refs/heads/main")
+	mustWrite("node_modules/pkg/index.js", "module.exports = {}")
+
+	t.Run("depth 0 summarizes subdirectories", func(t *testing.T) {
+		args := Args{Path: tempDir}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+
+		var res Result
+		if err := json.Unmarshal([]byte(result), &res); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v\n%s", err, result)
+		}
+
+		var src *Node
+		for i := range res.Tree.Children {
+			if res.Tree.Children[i].Name == "src" {
+				src = &res.Tree.Children[i]
+			}
+		}
+		if src == nil {
+			t.Fatal("Expected 'src' entry in tree")
+		}
+		if src.Type != "dir" || src.Children != nil {
+			t.Errorf("Expected 'src' to be summarized (no children) at depth 0, got: %+v", src)
+		}
+		if src.FileCount != 2 {
+			t.Errorf("Expected src.FileCount=2 (main.go + nested/deep.go), got %d", src.FileCount)
+		}
+		if src.DirCount != 1 {
+			t.Errorf("Expected src.DirCount=1 (nested), got %d", src.DirCount)
+		}
+	})
+
+	t.Run("depth expands nested directories", func(t *testing.T) {
+		args := Args{Path: tempDir, Depth: 2}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+
+		var res Result
+		if err := json.Unmarshal([]byte(result), &res); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+
+		var src *Node
+		for i := range res.Tree.Children {
+			if res.Tree.Children[i].Name == "src" {
+				src = &res.Tree.Children[i]
+			}
+		}
+		if src == nil || len(src.Children) != 2 {
+			t.Fatalf("Expected 'src' expanded with 2 children at depth 2, got: %+v", src)
+		}
+	})
+
+	t.Run("skips hidden and noise directories by default", func(t *testing.T) {
+		args := Args{Path: tempDir}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if strings.Contains(result, "node_modules") || strings.Contains(result, ".git") {
+			t.Errorf("Expected noise directories to be skipped, got: %s", result)
+		}
+	})
+
+	t.Run("include_hidden shows noise directories", func(t *testing.T) {
+		args := Args{Path: tempDir, IncludeHidden: true}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "node_modules") {
+			t.Errorf("Expected node_modules to show up with include_hidden, got: %s", result)
+		}
+	})
+
+	t.Run("respects .gitignore at root", func(t *testing.T) {
+		ignoreDir, err := os.MkdirTemp("", "dirtree_gitignore_test")
+		if err != nil {
+			t.Fatalf("Failed to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(ignoreDir)
+
+		if err := os.WriteFile(filepath.Join(ignoreDir, ".gitignore"), []byte("ignored.txt\n"), 0644); err != nil {
+			t.Fatalf("Failed to write .gitignore: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(ignoreDir, "ignored.txt"), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to write ignored.txt: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(ignoreDir, "kept.txt"), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to write kept.txt: %v", err)
+		}
+
+		args := Args{Path: ignoreDir}
+		argsJSON, _ := json.Marshal(args)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if strings.Contains(result, "ignored.txt") {
+			t.Errorf("Expected ignored.txt to be excluded via .gitignore, got: %s", result)
+		}
+		if !strings.Contains(result, "kept.txt") {
+			t.Errorf("Expected kept.txt to be present, got: %s", result)
+		}
+	})
+
+	t.Run("non-existent path", func(t *testing.T) {
+		args := Args{Path: "/fake/nonexistent/dir"}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "does not exist") {
+			t.Errorf("Expected 'does not exist' in result, got: %s", result)
+		}
+	})
+
+	t.Run("relative path is rejected", func(t *testing.T) {
+		args := Args{Path: "relative/dir"}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "must be absolute") {
+			t.Errorf("Expected 'must be absolute' in result, got: %s", result)
+		}
+	})
+
+	t.Run("depth out of range is rejected", func(t *testing.T) {
+		args := Args{Path: tempDir, Depth: 6}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "Error") {
+			t.Errorf("Expected error for out-of-range depth, got: %s", result)
+		}
+	})
+
+	t.Run("path is a file, not a directory", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "README.md")
+		args := Args{Path: filePath}
+		argsJSON, _ := json.Marshal(args)
+
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "is not a directory") {
+			t.Errorf("Expected 'is not a directory' in result, got: %s", result)
+		}
+	})
+}
+
+func TestDirTreeToolDefinition(t *testing.T) {
+	tool := NewTool()
+
+	if tool.Name() != "dir_tree" {
+		t.Errorf("Expected tool name 'dir_tree', got '%s'", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Tool description should not be empty")
+	}
+
+	def := tool.GetDefinition()
+	if def.Function.Name != "dir_tree" {
+		t.Errorf("Expected function name 'dir_tree', got '%s'", def.Function.Name)
+	}
+
+	params := def.Function.Parameters.(map[string]interface{})
+	props := params["properties"].(map[string]interface{})
+	for _, field := range []string{"path", "depth", "include_hidden"} {
+		if _, exists := props[field]; !exists {
+			t.Errorf("Expected '%s' parameter to exist", field)
+		}
+	}
+}
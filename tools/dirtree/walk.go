@@ -0,0 +1,179 @@
+package dirtree
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// walker accumulates state shared across a single Execute call's
+// recursive directory walk: the node budget and whether it's been hit,
+// and the options governing what gets skipped.
+type walker struct {
+	maxDepth       int
+	includeHidden  bool
+	maxNodes       int
+	nodeCount      int
+	truncated      bool
+	ignorePatterns []string
+}
+
+// build walks root and returns its tree node. The root itself is always
+// expanded regardless of maxDepth - depth counts the levels below it.
+func (w *walker) build(root string) Node {
+	w.nodeCount++
+	return Node{
+		Name:     filepath.Base(root),
+		Type:     "dir",
+		Children: w.listChildren(root, "", 1),
+	}
+}
+
+// listChildren returns the entries of dirPath, building each as a Node.
+// level is the nesting depth of these entries below root (root's direct
+// children are level 1).
+func (w *walker) listChildren(dirPath, relPath string, level int) []Node {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var children []Node
+	for _, entry := range entries {
+		if w.truncated {
+			break
+		}
+
+		name := entry.Name()
+		childRel := name
+		if relPath != "" {
+			childRel = relPath + "/" + name
+		}
+		if w.skip(name, childRel) {
+			continue
+		}
+
+		w.nodeCount++
+		if w.nodeCount > w.maxNodes {
+			w.truncated = true
+			break
+		}
+
+		if node, ok := w.buildEntry(filepath.Join(dirPath, name), childRel, name, level); ok {
+			children = append(children, node)
+		}
+	}
+	return children
+}
+
+// buildEntry builds the Node for a single directory entry: a file leaf,
+// an expanded directory (within maxDepth), or a summarized one (beyond it).
+func (w *walker) buildEntry(fullPath, relPath, name string, level int) (Node, bool) {
+	info, err := os.Lstat(fullPath)
+	if err != nil {
+		return Node{}, false
+	}
+	if !info.IsDir() {
+		return Node{Name: name, Type: "file"}, true
+	}
+
+	if level > w.maxDepth {
+		fileCount, dirCount := w.countRecursive(fullPath)
+		return Node{Name: name, Type: "dir", FileCount: fileCount, DirCount: dirCount}, true
+	}
+
+	return Node{
+		Name:     name,
+		Type:     "dir",
+		Children: w.listChildren(fullPath, relPath, level+1),
+	}, true
+}
+
+// countRecursive totals the files and directories under dir, for
+// summarizing a directory beyond maxDepth. It applies the same
+// hidden/noise/gitignore skip rules as listChildren, matched against
+// base names only - good enough for a summary count.
+func (w *walker) countRecursive(dir string) (fileCount, dirCount int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if w.skip(name, name) {
+			continue
+		}
+		if entry.IsDir() {
+			dirCount++
+			subFiles, subDirs := w.countRecursive(filepath.Join(dir, name))
+			fileCount += subFiles
+			dirCount += subDirs
+		} else {
+			fileCount++
+		}
+	}
+	return fileCount, dirCount
+}
+
+func (w *walker) skip(name, relPath string) bool {
+	if !w.includeHidden && isHiddenOrNoise(name) {
+		return true
+	}
+	return matchesIgnore(w.ignorePatterns, relPath)
+}
+
+// isHiddenOrNoise reports whether name is a dotfile or one of the common
+// directories that just add noise to a tree view.
+func isHiddenOrNoise(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	switch name {
+	case "node_modules", "__pycache__":
+		return true
+	}
+	return false
+}
+
+// readGitignore reads the gitignore-style pattern list from root/.gitignore,
+// or returns nil if it doesn't exist.
+func readGitignore(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// matchesIgnore reports whether relPath (forward-slash, root-relative) is
+// excluded by patterns: each pattern is tried against relPath's base name
+// and its full path, and a leading "!" re-includes a path an earlier
+// pattern excluded.
+func matchesIgnore(patterns []string, relPath string) bool {
+	base := filepath.Base(relPath)
+	excluded := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		pattern := strings.TrimSuffix(strings.TrimPrefix(p, "!"), "/")
+
+		matched, _ := filepath.Match(pattern, base)
+		if !matched {
+			matched, _ = filepath.Match(pattern, relPath)
+		}
+		if matched {
+			excluded = !negate
+		}
+	}
+	return excluded
+}
@@ -1,7 +1,9 @@
 package fileedit
 
 func GetDescription() string {
-	return `Edit files by replacing exact string matches. Ensures string uniqueness to avoid ambiguous replacements.
+	return `DEPRECATED: prefer modify_file, which supports multiple line-range edits per call and doesn't require the target text to be unique. Kept for back-compat.
+
+Edit files by replacing exact string matches. Ensures string uniqueness to avoid ambiguous replacements.
 
 Use this tool when you need to:
 - Replace specific text, code, or configuration values in files
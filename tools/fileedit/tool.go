@@ -1,12 +1,15 @@
 package fileedit
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
 	"github.com/revrost/go-openrouter"
 )
 
@@ -26,11 +29,19 @@ type Result struct {
 }
 
 // Tool represents the fileedit tool implementation
-type Tool struct{}
+type Tool struct {
+	fs brofs.FS
+}
 
-// NewTool creates a new fileedit tool instance
+// NewTool creates a new fileedit tool instance backed by the real filesystem.
 func NewTool() *Tool {
-	return &Tool{}
+	return &Tool{fs: brofs.NewOSFs()}
+}
+
+// NewToolWithFS creates a fileedit tool instance backed by the given
+// filesystem, e.g. brofs.NewMemFs() in tests.
+func NewToolWithFS(filesystem brofs.FS) *Tool {
+	return &Tool{fs: filesystem}
 }
 
 // Name returns the tool name
@@ -43,8 +54,9 @@ func (t *Tool) Description() string {
 	return GetDescription()
 }
 
-// Execute performs the file edit with the given arguments
-func (t *Tool) Execute(args json.RawMessage) (string, error) {
+// Execute performs the file edit with the given arguments. It completes
+// fast enough not to need progress reporting or cancellation.
+func (t *Tool) Execute(_ context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
 	var editArgs Args
 	if err := json.Unmarshal(args, &editArgs); err != nil {
 		return "", err
@@ -64,7 +76,7 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 	}
 
 	// Check if file exists and is readable
-	fileInfo, err := os.Stat(editArgs.Path)
+	fileInfo, err := t.fs.Stat(editArgs.Path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Sprintf("Error: file '%s' does not exist", editArgs.Path), nil
@@ -81,7 +93,7 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 	}
 
 	// Read the entire file
-	content, err := os.ReadFile(editArgs.Path)
+	content, err := t.fs.ReadFile(editArgs.Path)
 	if err != nil {
 		return fmt.Sprintf("Error reading file '%s': %s", editArgs.Path, err.Error()), nil
 	}
@@ -104,7 +116,7 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 	newContent := strings.Replace(contentStr, editArgs.OldString, editArgs.NewString, 1)
 
 	// Write the modified content back to the file
-	err = os.WriteFile(editArgs.Path, []byte(newContent), fileInfo.Mode())
+	err = t.fs.WriteFile(editArgs.Path, []byte(newContent), fileInfo.Mode())
 	if err != nil {
 		return fmt.Sprintf("Error writing to file '%s': %s", editArgs.Path, err.Error()), nil
 	}
@@ -1,30 +1,24 @@
 package fileedit
 
 import (
+	"context"
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
+
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
 )
 
 func TestFileEditTool(t *testing.T) {
-	tool := NewTool()
-
-	// Create temp directory for test files
-	tempDir, err := os.MkdirTemp("", "fileedit_test")
-	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
+	memFs := brofs.NewMemFs()
+	tool := NewToolWithFS(memFs)
 
 	// Test successful edit with unique string
 	t.Run("successful edit with unique string", func(t *testing.T) {
-		// Create test file
-		testFile := filepath.Join(tempDir, "test1.txt")
+		testFile := "/fake/test1.txt"
 		originalContent := "Hello world\nThis is a test\nGoodbye world"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -39,7 +33,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -56,7 +50,7 @@ func TestFileEditTool(t *testing.T) {
 		}
 
 		// Verify file content was changed
-		newContent, err := os.ReadFile(testFile)
+		newContent, err := memFs.ReadFile(testFile)
 		if err != nil {
 			t.Fatalf("Failed to read modified file: %v", err)
 		}
@@ -69,10 +63,9 @@ func TestFileEditTool(t *testing.T) {
 
 	// Test replacement with empty string (deletion)
 	t.Run("replace with empty string", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "test2.txt")
+		testFile := "/fake/test2.txt"
 		originalContent := "Keep this\nDelete this line\nKeep this too"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -87,16 +80,14 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
 
-		message := result
-		t.Logf("Delete line result: %s", message)
+		t.Logf("Delete line result: %s", result)
 
-		// Verify file content
-		newContent, err := os.ReadFile(testFile)
+		newContent, err := memFs.ReadFile(testFile)
 		if err != nil {
 			t.Fatalf("Failed to read modified file: %v", err)
 		}
@@ -109,10 +100,9 @@ func TestFileEditTool(t *testing.T) {
 
 	// Test string not found error
 	t.Run("string not found", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "test3.txt")
+		testFile := "/fake/test3.txt"
 		originalContent := "Hello world\nThis is a test"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -127,7 +117,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -142,8 +132,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Errorf("Expected 'not found' in error message, got: %s", message)
 		}
 
-		// Verify file was not modified
-		content, err := os.ReadFile(testFile)
+		content, err := memFs.ReadFile(testFile)
 		if err != nil {
 			t.Fatalf("Failed to read file: %v", err)
 		}
@@ -154,10 +143,9 @@ func TestFileEditTool(t *testing.T) {
 
 	// Test ambiguous string error (multiple occurrences)
 	t.Run("ambiguous string with multiple occurrences", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "test4.txt")
+		testFile := "/fake/test4.txt"
 		originalContent := "test line\nanother test line\nfinal test line"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -172,7 +160,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -190,8 +178,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Errorf("Expected uniqueness requirement in error message, got: %s", message)
 		}
 
-		// Verify file was not modified
-		content, err := os.ReadFile(testFile)
+		content, err := memFs.ReadFile(testFile)
 		if err != nil {
 			t.Fatalf("Failed to read file: %v", err)
 		}
@@ -203,7 +190,7 @@ func TestFileEditTool(t *testing.T) {
 	// Test non-existent file
 	t.Run("non-existent file", func(t *testing.T) {
 		args := Args{
-			Path:      "/nonexistent/file.txt",
+			Path:      "/fake/nonexistent/file.txt",
 			OldString: "old",
 			NewString: "new",
 		}
@@ -213,7 +200,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -242,7 +229,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -258,41 +245,10 @@ func TestFileEditTool(t *testing.T) {
 		}
 	})
 
-	// Test directory instead of file
-	t.Run("directory instead of file", func(t *testing.T) {
-		args := Args{
-			Path:      tempDir,
-			OldString: "old",
-			NewString: "new",
-		}
-
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
-
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
-		}
-
-		message := result
-		t.Logf("Directory result: %s", message)
-
-		if !strings.Contains(message, "Error") {
-			t.Errorf("Expected error message, got: %s", message)
-		}
-		if !strings.Contains(message, "is a directory") {
-			t.Errorf("Expected 'is a directory' in error message, got: %s", message)
-		}
-	})
-
 	// Test empty old_string
 	t.Run("empty old_string", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "test5.txt")
-		originalContent := "Some content"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		testFile := "/fake/test5.txt"
+		if err := memFs.WriteFile(testFile, []byte("Some content"), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -307,7 +263,7 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -325,10 +281,9 @@ func TestFileEditTool(t *testing.T) {
 
 	// Test multiline string replacement
 	t.Run("multiline string replacement", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "test6.txt")
+		testFile := "/fake/test6.txt"
 		originalContent := "Line 1\nOld block\nline 2\nline 3\nEnd"
-		err := os.WriteFile(testFile, []byte(originalContent), 0644)
-		if err != nil {
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
 			t.Fatalf("Failed to create test file: %v", err)
 		}
 
@@ -343,16 +298,14 @@ func TestFileEditTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
 
-		message := result
-		t.Logf("Multiline replacement result: %s", message)
+		t.Logf("Multiline replacement result: %s", result)
 
-		// Verify file content
-		newContent, err := os.ReadFile(testFile)
+		newContent, err := memFs.ReadFile(testFile)
 		if err != nil {
 			t.Fatalf("Failed to read modified file: %v", err)
 		}
@@ -386,7 +339,7 @@ func TestFileEditToolDefinition(t *testing.T) {
 	// Check required parameters
 	params := def.Function.Parameters.(map[string]interface{})
 	props := params["properties"].(map[string]interface{})
-	
+
 	requiredFields := []string{"path", "old_string", "new_string"}
 	for _, field := range requiredFields {
 		if _, exists := props[field]; !exists {
@@ -405,4 +358,4 @@ func TestFileEditToolDefinition(t *testing.T) {
 			t.Errorf("Unexpected required parameter: %s", param)
 		}
 	}
-}
\ No newline at end of file
+}
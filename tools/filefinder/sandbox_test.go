@@ -0,0 +1,41 @@
+package filefinder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/festeh/bro/internal/toolfake"
+	"github.com/festeh/bro/tools/filefinder"
+)
+
+// These tests drive filefinder through a toolfake.Sandbox instead of a
+// bare *Tool, so they exercise the real fd invocation against real files
+// rather than filefinder's in-process logic alone. They require fd on
+// PATH, same as filefinder itself does.
+func TestFileFinderToolSandbox(t *testing.T) {
+	sb := toolfake.New(t, map[string]string{
+		"a.go":      "package main\n",
+		"b.go":      "package main\n",
+		"readme.md": "# readme\n",
+		"sub/c.go":  "package sub\n",
+	})
+
+	t.Run("finds files by glob pattern", func(t *testing.T) {
+		result := sb.Run("filefinder", filefinder.Args{Pattern: "*.go", Path: sb.Root, Glob: true})
+		for _, want := range []string{"a.go", "b.go", "c.go"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected result to contain %q, got: %s", want, result)
+			}
+		}
+		if strings.Contains(result, "readme.md") {
+			t.Errorf("Expected readme.md to be excluded by the *.go glob, got: %s", result)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		result := sb.Run("filefinder", filefinder.Args{Pattern: "*.nonexistent", Path: sb.Root, Glob: true})
+		if !strings.Contains(result, "No files found") {
+			t.Errorf("Expected 'No files found', got: %s", result)
+		}
+	})
+}
@@ -1,33 +1,52 @@
 package filefinder
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
+	"github.com/festeh/bro/selection"
+	"github.com/festeh/bro/tools"
 	"github.com/revrost/go-openrouter"
 )
 
 type Args struct {
-	Pattern string `json:"pattern"`
-	Type    string `json:"type,omitempty"`    // file, directory, symlink, etc.
-	Glob    bool   `json:"glob,omitempty"`     // treat pattern as glob (default: regex)
+	Pattern        string `json:"pattern"`
+	Path           string `json:"path,omitempty"`           // directory to search in, defaults to current directory
+	Type           string `json:"type,omitempty"`           // file, directory, symlink, etc.
+	Glob           bool   `json:"glob,omitempty"`           // treat pattern as glob (default: regex)
+	IncludeIgnored bool   `json:"include_ignored,omitempty"` // include paths .broignore/.gitignore/defaults would otherwise hide
 }
 
 type Result struct {
-	Pattern   string   `json:"pattern"`
-	Files     []string `json:"files"`
-	Count     int      `json:"count"`
-	Error     string   `json:"error,omitempty"`
+	Pattern string   `json:"pattern"`
+	Files   []string `json:"files"`
+	Count   int      `json:"count"`
+	Error   string   `json:"error,omitempty"`
 }
 
 // Tool represents the filefinder tool implementation
-type Tool struct{}
+type Tool struct {
+	// ctx carries the selection.Config used to build the search root's
+	// SelectFunc (see selection.New), injected at construction time.
+	ctx tools.ToolContext
+}
 
-// NewTool creates a new filefinder tool instance
+// NewTool creates a new filefinder tool instance using the default
+// ToolContext (~/.bro/ignore.txt, or no extra excludes if that can't be read).
 func NewTool() *Tool {
-	return &Tool{}
+	return NewToolWithContext(tools.DefaultToolContext())
+}
+
+// NewToolWithContext creates a filefinder tool instance that builds its
+// selection.SelectFunc from ctx instead of the default one.
+func NewToolWithContext(ctx tools.ToolContext) *Tool {
+	return &Tool{ctx: ctx}
 }
 
 // Name returns the tool name
@@ -40,54 +59,99 @@ func (t *Tool) Description() string {
 	return GetDescription()
 }
 
-// Execute runs the fd command with the given arguments
-func (t *Tool) Execute(args json.RawMessage) (interface{}, error) {
+// Execute runs the fd command with the given arguments, reporting each
+// matched path to progress as fd emits it, then drops any result the
+// configured selection.SelectFunc rejects. ctx is passed straight to
+// exec.CommandContext, so canceling it kills the fd invocation.
+func (t *Tool) Execute(ctx context.Context, args json.RawMessage, progress tools.ProgressReporter) (string, error) {
 	var findArgs Args
 	if err := json.Unmarshal(args, &findArgs); err != nil {
-		return nil, err
+		return "", err
 	}
-	
+	if progress == nil {
+		progress = tools.NoopProgress
+	}
+
+	root := findArgs.Path
+	if root == "" {
+		root = "."
+	}
+
 	// Build fd command arguments
 	cmdArgs := []string{}
-	
+
 	// Add glob flag if needed
 	if findArgs.Glob {
 		cmdArgs = append(cmdArgs, "--glob")
 	}
-	
+
 	// Add pattern
 	if findArgs.Pattern != "" {
 		cmdArgs = append(cmdArgs, findArgs.Pattern)
+	} else {
+		cmdArgs = append(cmdArgs, ".")
 	}
-	
+	cmdArgs = append(cmdArgs, root)
+
 	// Add type filter
 	if findArgs.Type != "" {
 		cmdArgs = append(cmdArgs, "--type", findArgs.Type)
 	}
-	
-	// Execute fd command
-	cmd := exec.Command("fd", cmdArgs...)
-	
-	stdout, err := cmd.Output()
-	
+
+	// Execute fd command, streaming stdout line by line so progress can
+	// report each found path as fd emits it.
+	cmd := exec.CommandContext(ctx, "fd", cmdArgs...)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Sprintf("Error searching for pattern '%s': %s", findArgs.Pattern, err.Error()), nil
+	}
+
+	var rawLines []string
+	bytes := 0
+	scanner := bufio.NewScanner(stdoutPipe)
+	for scanner.Scan() {
+		line := scanner.Text()
+		rawLines = append(rawLines, line)
+		bytes += len(line)
+		progress.Report(tools.ProgressUpdate{Line: line, Lines: len(rawLines), Bytes: bytes})
+	}
+
+	err = cmd.Wait()
+
 	// Build assistant message response
 	var message strings.Builder
-	
+
 	if err != nil {
 		// Handle errors
 		message.WriteString(fmt.Sprintf("Error searching for pattern '%s': ", findArgs.Pattern))
-		if exitError, ok := err.(*exec.ExitError); ok {
-			message.WriteString(string(exitError.Stderr))
+		if stderr.Len() > 0 {
+			message.WriteString(stderr.String())
 		} else {
 			message.WriteString(err.Error())
 		}
 		return message.String(), nil
 	}
-	
-	// Parse output
-	output := strings.TrimSpace(string(stdout))
-	
-	if output == "" {
+
+	var files []string
+	if len(rawLines) > 0 {
+		filter, _ := selection.New(root, t.ctx.Selection, findArgs.IncludeIgnored)
+		for _, file := range rawLines {
+			fi, statErr := os.Stat(file)
+			if statErr == nil && !filter(filepath.Clean(file), fi) {
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+
+	if len(files) == 0 {
 		// No files found
 		message.WriteString(fmt.Sprintf("No files found matching pattern '%s'", findArgs.Pattern))
 		if findArgs.Type != "" {
@@ -95,26 +159,25 @@ func (t *Tool) Execute(args json.RawMessage) (interface{}, error) {
 		}
 	} else {
 		// Files found
-		files := strings.Split(output, "\n")
 		count := len(files)
-		
+
 		if count == 1 {
 			message.WriteString(fmt.Sprintf("Found 1 file matching pattern '%s':", findArgs.Pattern))
 		} else {
 			message.WriteString(fmt.Sprintf("Found %d files matching pattern '%s':", count, findArgs.Pattern))
 		}
-		
+
 		if findArgs.Type != "" {
 			message.WriteString(fmt.Sprintf(" (type: %s)", findArgs.Type))
 		}
-		
+
 		message.WriteString("\n")
 		for _, file := range files {
 			message.WriteString(fmt.Sprintf("- %s\n", file))
 		}
 	}
-	
-	return message.String(), nil
+
+	return tools.TruncateResult(message.String(), t.ctx.OutputPolicy, t.ctx.OutputCache), nil
 }
 
 // GetDefinition returns the OpenRouter tool definition
@@ -131,6 +194,10 @@ func (t *Tool) GetDefinition() openrouter.Tool {
 						"type":        "string",
 						"description": "Pattern or regex to search for files and directories",
 					},
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory to search in (defaults to current directory)",
+					},
 					"type": map[string]interface{}{
 						"type":        "string",
 						"description": "Filter by type: file, directory, symlink, executable, empty, socket, pipe",
@@ -140,9 +207,13 @@ func (t *Tool) GetDefinition() openrouter.Tool {
 						"type":        "boolean",
 						"description": "Treat pattern as glob instead of regex (default: false)",
 					},
+					"include_ignored": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Include paths .broignore/.gitignore would otherwise hide (default: false). Hardcoded excludes like .git and node_modules still apply.",
+					},
 				},
 				"required": []string{"pattern"},
 			},
 		},
 	}
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package filefinder_test
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/festeh/bro/environment"
 	"github.com/festeh/bro/openrouter"
+	"github.com/festeh/bro/tools"
 	"github.com/festeh/bro/tools/filefinder"
 )
 
@@ -53,7 +55,7 @@ func TestFileFinderToolWithAI(t *testing.T) {
 			if model.currentCallID != "" && model.currentArgs != "" {
 				t.Logf("Executing accumulated tool call %s with args: %s", model.currentCallID, model.currentArgs)
 				tool := filefinder.NewTool()
-				result, err := tool.Execute([]byte(model.currentArgs))
+				result, err := tool.Execute(context.Background(), []byte(model.currentArgs), tools.NoopProgress)
 				if err != nil {
 					model.error = err.Error()
 					model.hasError = true
@@ -101,7 +103,8 @@ func TestFileFinderToolWithAI(t *testing.T) {
 	// Ask AI to find all tool_test.go files using the filefinder tool
 	userMessage := "I need you to use the filefinder tool to find all files named 'tool_test.go' in the current directory and subdirectories. Use a glob pattern to match these files. You must use the filefinder tool for this."
 	
-	err = client.SendMessage(userMessage, handler)
+	messages := openrouter.ChatMessagesToOpenRouter([]openrouter.Renderable{openrouter.NewUserMessage(userMessage)})
+	err = client.SendMessages(messages, handler)
 	if err != nil {
 		t.Fatalf("Failed to send message: %v", err)
 	}
@@ -186,7 +189,7 @@ func TestFileFinderBasic(t *testing.T) {
 		t.Fatalf("Failed to marshal args: %v", err)
 	}
 	
-	result, err := tool.Execute(argsJSON)
+	result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 	if err != nil {
 		t.Fatalf("Tool execution failed: %v", err)
 	}
@@ -0,0 +1,126 @@
+package filepatch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitLines splits content into its lines, reporting separately whether
+// it ended with a trailing newline so joinLines can reconstruct it
+// exactly. Mirrors modifyfile's helper of the same name.
+func splitLines(content string) (lines []string, trailingNewline bool) {
+	if content == "" {
+		return nil, false
+	}
+	trailingNewline = strings.HasSuffix(content, "\n")
+	if trailingNewline {
+		content = content[:len(content)-1]
+	}
+	return strings.Split(content, "\n"), trailingNewline
+}
+
+// joinLines is the inverse of splitLines.
+func joinLines(lines []string, trailingNewline bool) string {
+	joined := strings.Join(lines, "\n")
+	if trailingNewline && len(lines) > 0 {
+		joined += "\n"
+	}
+	return joined
+}
+
+// spliceLines replaces lines[from:to] (0-indexed, to exclusive) with
+// replacement, built on a fresh slice so it can't corrupt lines when
+// replacement shares backing storage with it.
+func spliceLines(lines []string, from, to int, replacement []string) []string {
+	result := make([]string, 0, len(lines)-(to-from)+len(replacement))
+	result = append(result, lines[:from]...)
+	result = append(result, replacement...)
+	result = append(result, lines[to:]...)
+	return result
+}
+
+// matchesAt reports whether want occurs in lines starting at index at.
+func matchesAt(lines, want []string, at int) bool {
+	if at < 0 || at+len(want) > len(lines) {
+		return false
+	}
+	for i, w := range want {
+		if lines[at+i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyResult is what happened when applying a FilePatch to a file's
+// content: the resulting lines (unchanged where a hunk was rejected),
+// how many hunks applied, and which ones didn't.
+type ApplyResult struct {
+	Lines    []string
+	Applied  int
+	Rejected []Hunk
+}
+
+// Apply tries every hunk in fp against lines in file order: first at its
+// recorded OldStart position (adjusted for the net line count change of
+// already-applied hunks before it), then within +/-fuzz lines of that
+// position if the exact spot doesn't match. A hunk whose context can't be
+// found anywhere in that window is rejected rather than aborting the rest
+// of the file - the same best-effort-plus-.rej behavior the `patch`
+// command has.
+func Apply(lines []string, fp FilePatch, fuzz int) ApplyResult {
+	result := append([]string(nil), lines...)
+	var rejected []Hunk
+	applied := 0
+	offset := 0
+
+	for _, h := range fp.Hunks {
+		want := h.oldContext()
+		base := h.OldStart - 1 + offset
+
+		at := -1
+		switch {
+		case matchesAt(result, want, base):
+			at = base
+		default:
+			for d := 1; d <= fuzz && at == -1; d++ {
+				if matchesAt(result, want, base-d) {
+					at = base - d
+				} else if matchesAt(result, want, base+d) {
+					at = base + d
+				}
+			}
+		}
+
+		if at == -1 {
+			rejected = append(rejected, h)
+			continue
+		}
+
+		replacement := h.newText()
+		result = spliceLines(result, at, at+len(want), replacement)
+		offset += len(replacement) - len(want)
+		applied++
+	}
+
+	return ApplyResult{Lines: result, Applied: applied, Rejected: rejected}
+}
+
+// renderRejects renders hunks that couldn't be placed as their own
+// standalone unified diff against path, the same format the `patch`
+// command writes to its .rej files, so the model (or a human) can inspect
+// and hand-apply them.
+func renderRejects(path string, hunks []Hunk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a%s\n", path)
+	fmt.Fprintf(&b, "+++ b%s\n", path)
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, l := range h.Lines {
+			b.WriteByte(l.Kind)
+			b.WriteString(l.Text)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
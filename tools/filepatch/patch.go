@@ -0,0 +1,187 @@
+package filepatch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HunkLine is one line of a hunk body: a context line (' '), a removed
+// line ('-'), or an added line ('+').
+type HunkLine struct {
+	Kind byte
+	Text string
+}
+
+// Hunk is one "@@ ... @@" section of a unified diff.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []HunkLine
+}
+
+// oldContext returns the lines a hunk expects to find in the file being
+// patched: every line except pure additions.
+func (h Hunk) oldContext() []string {
+	var out []string
+	for _, l := range h.Lines {
+		if l.Kind != '+' {
+			out = append(out, l.Text)
+		}
+	}
+	return out
+}
+
+// newText returns the lines oldContext is replaced by: every line except
+// pure removals.
+func (h Hunk) newText() []string {
+	var out []string
+	for _, l := range h.Lines {
+		if l.Kind != '-' {
+			out = append(out, l.Text)
+		}
+	}
+	return out
+}
+
+// FilePatch is every hunk targeting one file, as delimited by a
+// "--- a/path" / "+++ b/path" header pair.
+type FilePatch struct {
+	OldPath string
+	NewPath string
+	Hunks   []Hunk
+}
+
+// Path returns the file this patch targets: NewPath, unless the patch
+// deletes the file (NewPath is "/dev/null"), in which case OldPath.
+func (fp FilePatch) Path() string {
+	if fp.NewPath == "" || fp.NewPath == "/dev/null" {
+		return fp.OldPath
+	}
+	return fp.NewPath
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParsePatch parses a standard unified diff - the format `diff -u` and
+// `git diff` produce, and the shape fileedit's modifyfile sibling
+// unifiedDiff renders - into one FilePatch per file header. It's
+// deliberately strict about the header shape so a malformed patch is
+// rejected up front rather than silently misapplied.
+func ParsePatch(patch string) ([]FilePatch, error) {
+	lines := strings.Split(patch, "\n")
+	var files []FilePatch
+
+	i := 0
+	for i < len(lines) {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			i++
+			continue
+		}
+		oldPath := stripPatchPrefix(strings.TrimPrefix(lines[i], "--- "))
+		i++
+		if i >= len(lines) || !strings.HasPrefix(lines[i], "+++ ") {
+			return nil, fmt.Errorf("line %d: expected a '+++ ' header after '--- %s'", i+1, oldPath)
+		}
+		newPath := stripPatchPrefix(strings.TrimPrefix(lines[i], "+++ "))
+		i++
+
+		var hunks []Hunk
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@ ") {
+			hunk, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			hunks = append(hunks, hunk)
+			i = next
+		}
+		if len(hunks) == 0 {
+			return nil, fmt.Errorf("file %q: no hunks follow its '--- '/'+++ ' header", newPath)
+		}
+
+		files = append(files, FilePatch{OldPath: oldPath, NewPath: newPath, Hunks: hunks})
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no '--- '/'+++ ' file headers found in patch")
+	}
+	return files, nil
+}
+
+// stripPatchPrefix cleans up one side of a "--- "/"+++ " header: it drops
+// a trailing tab-separated timestamp (e.g. "\t2026-07-29 10:00:00") and a
+// leading "a"/"b" marker, if present. Bro's paths are always absolute, so
+// the marker is a single letter directly followed by the path's own
+// leading "/" (as modifyfile's unifiedDiff renders it: "--- a" + path) -
+// not the two-character "a/" git uses for its repo-relative paths.
+func stripPatchPrefix(side string) string {
+	if tab := strings.IndexByte(side, '\t'); tab != -1 {
+		side = side[:tab]
+	}
+	side = strings.TrimSpace(side)
+	if side == "/dev/null" {
+		return side
+	}
+	if len(side) > 1 && (side[0] == 'a' || side[0] == 'b') && side[1] == '/' {
+		return side[1:]
+	}
+	return side
+}
+
+// parseHunk parses the "@@ -l,s +l,s @@" header at lines[start] and the
+// hunk body that follows, stopping once it has consumed as many old- and
+// new-file lines as the header declared. It returns the index just past
+// the hunk so the caller can keep scanning for the next one.
+func parseHunk(lines []string, start int) (Hunk, int, error) {
+	m := hunkHeaderRe.FindStringSubmatch(lines[start])
+	if m == nil {
+		return Hunk{}, 0, fmt.Errorf("line %d: malformed hunk header %q", start+1, lines[start])
+	}
+
+	oldStart, _ := strconv.Atoi(m[1])
+	oldLines := 1
+	if m[2] != "" {
+		oldLines, _ = strconv.Atoi(m[2])
+	}
+	newStart, _ := strconv.Atoi(m[3])
+	newLines := 1
+	if m[4] != "" {
+		newLines, _ = strconv.Atoi(m[4])
+	}
+
+	hunk := Hunk{OldStart: oldStart, OldLines: oldLines, NewStart: newStart, NewLines: newLines}
+
+	i := start + 1
+	oldSeen, newSeen := 0, 0
+	for i < len(lines) && (oldSeen < oldLines || newSeen < newLines) {
+		line := lines[i]
+		if line == `\ No newline at end of file` {
+			i++
+			continue
+		}
+		if line == "" {
+			hunk.Lines = append(hunk.Lines, HunkLine{Kind: ' ', Text: ""})
+			oldSeen++
+			newSeen++
+			i++
+			continue
+		}
+		switch line[0] {
+		case ' ':
+			hunk.Lines = append(hunk.Lines, HunkLine{Kind: ' ', Text: line[1:]})
+			oldSeen++
+			newSeen++
+		case '-':
+			hunk.Lines = append(hunk.Lines, HunkLine{Kind: '-', Text: line[1:]})
+			oldSeen++
+		case '+':
+			hunk.Lines = append(hunk.Lines, HunkLine{Kind: '+', Text: line[1:]})
+			newSeen++
+		default:
+			return Hunk{}, 0, fmt.Errorf("line %d: expected a hunk line starting with ' ', '-', or '+', got %q", i+1, line)
+		}
+		i++
+	}
+	return hunk, i, nil
+}
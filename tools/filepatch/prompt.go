@@ -0,0 +1,28 @@
+package filepatch
+
+func GetDescription() string {
+	return `Apply a standard unified diff to one or more files in a single call. Unlike fileedit (which requires old_string to be an exact, unique match) and modify_file (which requires exact line numbers), filepatch tolerates drift: each hunk is matched at its recorded position first, then within fuzz lines of it by searching for its surrounding context.
+
+Use this tool when you need to:
+- Apply a patch you or another tool already generated as a unified diff
+- Make a coherent multi-file change in one call instead of one fileedit/modify_file round trip per file
+- Apply an edit to a file that may have shifted slightly since the diff was written (extra blank lines, a nearby edit, etc.)
+
+The tool will:
+- Parse every "--- a/path" / "+++ b/path" / "@@ -l,s +l,s @@" file section in patch
+- Validate every file the patch touches exists and is readable before changing anything, so a bad path in a multi-file patch can't leave some files changed and others untouched
+- For each file, try to place each hunk exactly at its recorded line, then within +/-fuzz lines of drift
+- Apply every hunk that matches; any hunk that doesn't match anywhere in that window is left unapplied and written to <path>.rej as its own unified diff, instead of failing the whole file
+- Return a one-line-per-file summary of how many hunks applied and where any .rej file was written
+
+Important considerations:
+- File paths in the diff headers must be absolute, with an optional single-letter "a"/"b" marker directly before the leading slash (e.g. "--- a/home/user/app.go"), matching the unified diffs modify_file itself returns - not git's two-character "a/"-plus-relative-path convention
+- fuzz defaults to 2 if omitted; set it higher for a file you expect has drifted further from the diff's base, or to 0 to require exact placement
+- Set dry_run to true to see what would apply/reject without writing anything
+- A hunk's context must still be found somewhere in the file - filepatch tolerates line drift, not content drift
+
+Examples of good use cases:
+- filepatch: {"patch": "--- a/home/user/src/app.go\n+++ b/home/user/src/app.go\n@@ -10,3 +10,3 @@\n context\n-old line\n+new line\n context\n"}
+- filepatch: {"patch": "<multi-file diff>", "fuzz": 5, "dry_run": true}
+`
+}
@@ -0,0 +1,192 @@
+package filepatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// defaultFuzz is how many lines of drift a hunk's context is searched
+// across when it doesn't match at its recorded position, if the caller
+// doesn't set fuzz explicitly.
+const defaultFuzz = 2
+
+type Args struct {
+	Patch  string `json:"patch"`
+	Fuzz   int    `json:"fuzz,omitempty"`
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// Tool applies a standard unified diff to one or more files in a single
+// call: each file's hunks are matched at their recorded position first,
+// then within +/-Fuzz lines of drift, with any hunk that still doesn't
+// match written out as path+".rej" instead of aborting the whole patch.
+type Tool struct {
+	fs brofs.FS
+}
+
+// NewTool creates a new filepatch tool instance backed by the real filesystem.
+func NewTool() *Tool {
+	return &Tool{fs: brofs.NewOSFs()}
+}
+
+// NewToolWithFS creates a filepatch tool instance backed by the given
+// filesystem, e.g. brofs.NewMemFs() in tests.
+func NewToolWithFS(filesystem brofs.FS) *Tool {
+	return &Tool{fs: filesystem}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "filepatch"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return GetDescription()
+}
+
+// target is one file a patch touches, loaded and validated up front so
+// Execute can apply every file's hunks only after confirming every file
+// in the patch is actually reachable.
+type target struct {
+	path            string
+	lines           []string
+	trailingNewline bool
+	mode            os.FileMode
+}
+
+// Execute parses patchArgs.Patch, validates every file it touches exists
+// and is readable, then applies each file's hunks (writing the result
+// unless DryRun is set) and reports what happened per file. Validating
+// every target before writing any of them means a patch naming one bad
+// path can't leave earlier files in the patch changed and later ones
+// untouched.
+func (t *Tool) Execute(_ context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var patchArgs Args
+	if err := json.Unmarshal(args, &patchArgs); err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(patchArgs.Patch) == "" {
+		return "Error: patch is required", nil
+	}
+	// An explicit fuzz of 0 (exact placement only) can't be told apart from
+	// an omitted one through patchArgs alone, so check the raw arguments.
+	var rawArgs map[string]json.RawMessage
+	_ = json.Unmarshal(args, &rawArgs)
+	fuzz := patchArgs.Fuzz
+	if _, set := rawArgs["fuzz"]; !set || fuzz < 0 {
+		fuzz = defaultFuzz
+	}
+
+	files, err := ParsePatch(patchArgs.Patch)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err.Error()), nil
+	}
+
+	targets := make([]target, len(files))
+	for i, fp := range files {
+		path := fp.Path()
+		if !filepath.IsAbs(path) {
+			return fmt.Sprintf("Error: path must be absolute, got %q", path), nil
+		}
+
+		fileInfo, err := t.fs.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Sprintf("Error: file %q does not exist", path), nil
+			}
+			if os.IsPermission(err) {
+				return fmt.Sprintf("Error: permission denied accessing file %q", path), nil
+			}
+			return fmt.Sprintf("Error accessing file %q: %s", path, err.Error()), nil
+		}
+		if fileInfo.IsDir() {
+			return fmt.Sprintf("Error: %q is a directory, not a file", path), nil
+		}
+
+		content, err := t.fs.ReadFile(path)
+		if err != nil {
+			return fmt.Sprintf("Error reading file %q: %s", path, err.Error()), nil
+		}
+
+		lines, trailingNewline := splitLines(string(content))
+		targets[i] = target{path: path, lines: lines, trailingNewline: trailingNewline, mode: fileInfo.Mode()}
+	}
+
+	var summary strings.Builder
+	for i, fp := range files {
+		tg := targets[i]
+		result := Apply(tg.lines, fp, fuzz)
+
+		if patchArgs.DryRun {
+			fmt.Fprintf(&summary, "%s: would apply %d/%d hunk(s)", tg.path, result.Applied, len(fp.Hunks))
+			if len(result.Rejected) > 0 {
+				fmt.Fprintf(&summary, ", %d would be rejected", len(result.Rejected))
+			}
+			summary.WriteString("\n")
+			continue
+		}
+
+		if result.Applied > 0 {
+			newContent := joinLines(result.Lines, tg.trailingNewline)
+			if err := t.fs.WriteFile(tg.path, []byte(newContent), tg.mode); err != nil {
+				return fmt.Sprintf("Error writing file %q: %s", tg.path, err.Error()), nil
+			}
+		}
+
+		fmt.Fprintf(&summary, "%s: applied %d/%d hunk(s)", tg.path, result.Applied, len(fp.Hunks))
+		if len(result.Rejected) > 0 {
+			rejPath := tg.path + ".rej"
+			if err := t.fs.WriteFile(rejPath, []byte(renderRejects(tg.path, result.Rejected)), 0644); err != nil {
+				fmt.Fprintf(&summary, " (failed to write %s: %s)", rejPath, err.Error())
+			} else {
+				fmt.Fprintf(&summary, ", %d rejected -> %s", len(result.Rejected), rejPath)
+			}
+		}
+		summary.WriteString("\n")
+	}
+
+	verb := "Applied"
+	if patchArgs.DryRun {
+		verb = "Dry run for"
+	}
+	return fmt.Sprintf("%s patch across %d file(s) (fuzz=%d):\n%s", verb, len(files), fuzz, strings.TrimSpace(summary.String())), nil
+}
+
+// GetDefinition returns the OpenRouter tool definition
+func (t *Tool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"patch": map[string]interface{}{
+						"type":        "string",
+						"description": "A standard unified diff (--- a/path, +++ b/path, @@ -l,s +l,s @@ hunks), optionally covering multiple files",
+					},
+					"fuzz": map[string]interface{}{
+						"type":        "integer",
+						"description": "Lines of drift to tolerate when a hunk's context doesn't match at its recorded position (default 2)",
+					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Report what would happen without writing any file",
+					},
+				},
+				"required": []string{"patch"},
+			},
+		},
+	}
+}
@@ -0,0 +1,282 @@
+package filepatch
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
+)
+
+func mustMarshal(t *testing.T, args Args) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("Failed to marshal args: %v", err)
+	}
+	return data
+}
+
+func TestFilePatchTool(t *testing.T) {
+	t.Run("applies a hunk at its exact position", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test1.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/test1.txt\n+++ b/fake/test1.txt\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "applied 1/1 hunk(s)") {
+			t.Errorf("Expected all hunks applied, got: %s", result)
+		}
+
+		newContent, err := memFs.ReadFile(testFile)
+		if err != nil {
+			t.Fatalf("Failed to read modified file: %v", err)
+		}
+		if string(newContent) != "one\nTWO\nthree\nfour\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("applies a hunk with fuzzy drift", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test2.txt"
+		// Hunk below claims "two" is at line 2, but an extra line inserted
+		// above has pushed it down to line 3.
+		if err := memFs.WriteFile(testFile, []byte("zero\none\ntwo\nthree\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/test2.txt\n+++ b/fake/test2.txt\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch, Fuzz: 3}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "applied 1/1 hunk(s)") {
+			t.Errorf("Expected fuzzy match to apply, got: %s", result)
+		}
+
+		newContent, _ := memFs.ReadFile(testFile)
+		if string(newContent) != "zero\none\nTWO\nthree\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("multi-file patch applies to every file", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		fileA := "/fake/a.txt"
+		fileB := "/fake/b.txt"
+		if err := memFs.WriteFile(fileA, []byte("a1\na2\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		if err := memFs.WriteFile(fileB, []byte("b1\nb2\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/a.txt\n+++ b/fake/a.txt\n@@ -1,1 +1,1 @@\n-a1\n+A1\n" +
+			"--- a/fake/b.txt\n+++ b/fake/b.txt\n@@ -1,1 +1,1 @@\n-b1\n+B1\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "across 2 file(s)") {
+			t.Errorf("Expected both files in summary, got: %s", result)
+		}
+
+		contentA, _ := memFs.ReadFile(fileA)
+		contentB, _ := memFs.ReadFile(fileB)
+		if string(contentA) != "A1\na2\n" || string(contentB) != "B1\nb2\n" {
+			t.Errorf("Unexpected content: a=%q b=%q", contentA, contentB)
+		}
+	})
+
+	t.Run("unmatched hunk is rejected and written as a .rej file", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test3.txt"
+		originalContent := "one\ntwo\nthree\n"
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/test3.txt\n+++ b/fake/test3.txt\n@@ -2,1 +2,1 @@\n-nonexistent\n+replacement\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch, Fuzz: 1}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "applied 0/1 hunk(s)") || !strings.Contains(result, ".rej") {
+			t.Errorf("Expected a rejected hunk reported, got: %s", result)
+		}
+
+		content, _ := memFs.ReadFile(testFile)
+		if string(content) != originalContent {
+			t.Errorf("File should not have been modified by a rejected hunk")
+		}
+
+		rej, err := memFs.ReadFile(testFile + ".rej")
+		if err != nil {
+			t.Fatalf("Expected a .rej file to be written: %v", err)
+		}
+		if !strings.Contains(string(rej), "-nonexistent") || !strings.Contains(string(rej), "+replacement") {
+			t.Errorf("Expected .rej to contain the unapplied hunk, got: %s", rej)
+		}
+	})
+
+	t.Run("dry run does not write any file", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test4.txt"
+		originalContent := "one\ntwo\n"
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/test4.txt\n+++ b/fake/test4.txt\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch, DryRun: true}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "would apply 1/1 hunk(s)") {
+			t.Errorf("Expected dry-run summary, got: %s", result)
+		}
+
+		content, _ := memFs.ReadFile(testFile)
+		if string(content) != originalContent {
+			t.Errorf("Dry run should not modify the file, got: %q", content)
+		}
+	})
+
+	t.Run("one bad path in a multi-file patch leaves every file untouched", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		fileA := "/fake/ok.txt"
+		originalContent := "one\ntwo\n"
+		if err := memFs.WriteFile(fileA, []byte(originalContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		patch := "--- a/fake/ok.txt\n+++ b/fake/ok.txt\n@@ -1,1 +1,1 @@\n-one\n+ONE\n" +
+			"--- a/fake/missing.txt\n+++ b/fake/missing.txt\n@@ -1,1 +1,1 @@\n-x\n+y\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "does not exist") {
+			t.Errorf("Expected a does-not-exist error, got: %s", result)
+		}
+
+		content, _ := memFs.ReadFile(fileA)
+		if string(content) != originalContent {
+			t.Errorf("Earlier file in the patch should not have been touched, got: %q", content)
+		}
+	})
+
+	t.Run("relative path is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+
+		patch := "--- relative/test.txt\n+++ relative/test.txt\n@@ -1,1 +1,1 @@\n-x\n+y\n"
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: patch}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "must be absolute") {
+			t.Errorf("Expected 'must be absolute' in result, got: %s", result)
+		}
+	})
+
+	t.Run("empty patch is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: ""}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "patch is required") {
+			t.Errorf("Expected 'patch is required' in result, got: %s", result)
+		}
+	})
+
+	t.Run("malformed patch is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+
+		result, err := tool.Execute(context.Background(), mustMarshal(t, Args{Patch: "not a patch"}), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "Error") {
+			t.Errorf("Expected an error for a malformed patch, got: %s", result)
+		}
+	})
+}
+
+func TestFilePatchToolDefinition(t *testing.T) {
+	tool := NewTool()
+
+	if tool.Name() != "filepatch" {
+		t.Errorf("Expected tool name 'filepatch', got '%s'", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Tool description should not be empty")
+	}
+
+	def := tool.GetDefinition()
+	if def.Function.Name != "filepatch" {
+		t.Errorf("Expected function name 'filepatch', got '%s'", def.Function.Name)
+	}
+
+	params := def.Function.Parameters.(map[string]interface{})
+	props := params["properties"].(map[string]interface{})
+	for _, field := range []string{"patch", "fuzz", "dry_run"} {
+		if _, exists := props[field]; !exists {
+			t.Errorf("Expected '%s' parameter to exist", field)
+		}
+	}
+}
+
+func TestParsePatch(t *testing.T) {
+	t.Run("parses a/b-prefixed absolute paths and a single hunk", func(t *testing.T) {
+		patch := "--- a/src/app.go\n+++ b/src/app.go\n@@ -1,2 +1,2 @@\n context\n-old\n+new\n"
+		files, err := ParsePatch(patch)
+		if err != nil {
+			t.Fatalf("ParsePatch failed: %v", err)
+		}
+		if len(files) != 1 {
+			t.Fatalf("Expected 1 file, got %d", len(files))
+		}
+		if files[0].OldPath != "/src/app.go" || files[0].NewPath != "/src/app.go" {
+			t.Errorf("Expected a/b markers stripped down to the absolute path, got old=%q new=%q", files[0].OldPath, files[0].NewPath)
+		}
+		if len(files[0].Hunks) != 1 {
+			t.Fatalf("Expected 1 hunk, got %d", len(files[0].Hunks))
+		}
+		h := files[0].Hunks[0]
+		if h.OldStart != 1 || h.OldLines != 2 || h.NewStart != 1 || h.NewLines != 2 {
+			t.Errorf("Unexpected hunk header: %+v", h)
+		}
+	})
+
+	t.Run("rejects a patch with no file headers", func(t *testing.T) {
+		if _, err := ParsePatch("just some text\n"); err == nil {
+			t.Error("Expected an error for a patch with no headers")
+		}
+	})
+
+	t.Run("rejects a missing +++ header", func(t *testing.T) {
+		if _, err := ParsePatch("--- a/x.txt\n@@ -1,1 +1,1 @@\n-a\n+b\n"); err == nil {
+			t.Error("Expected an error for a missing '+++' header")
+		}
+	})
+}
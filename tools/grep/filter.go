@@ -0,0 +1,26 @@
+package grep
+
+import (
+	"strings"
+
+	"github.com/festeh/bro/selection"
+)
+
+// SelectFunc is an alias for selection.SelectFunc, kept so shard.go and
+// callers outside this package don't need to import selection directly.
+type SelectFunc = selection.SelectFunc
+
+// excludeGlobs converts gitignore-style patterns into ripgrep --glob
+// exclusion arguments. Negated patterns (re-includes) are passed through
+// as-is so ripgrep's own glob negation applies.
+func excludeGlobs(patterns []string) []string {
+	var globs []string
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			globs = append(globs, p)
+			continue
+		}
+		globs = append(globs, "!"+p)
+	}
+	return globs
+}
@@ -0,0 +1,17 @@
+package grep
+
+import "testing"
+
+func TestExcludeGlobs(t *testing.T) {
+	globs := excludeGlobs([]string{"*.log", "!keep.log"})
+	expected := []string{"!*.log", "!keep.log"}
+
+	if len(globs) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, globs)
+	}
+	for i, g := range globs {
+		if g != expected[i] {
+			t.Errorf("Expected glob %q at index %d, got %q", expected[i], i, g)
+		}
+	}
+}
@@ -25,17 +25,24 @@ Search features:
 - Context lines before and after matches
 - Path filtering and exclusions
 - Recursive directory searching
+- Searching multiple roots in one call, sharded across concurrent workers
+  for large trees via paths/shards
 
 Important considerations:
 - Use specific patterns to avoid too many results
 - Add context lines to understand match surroundings
 - Specify paths to narrow search scope
 - Use proper regex escaping for special characters
+- Paths listed in .broignore or .gitignore at the search root are excluded
+  automatically; set include_ignored to search them anyway, and use
+  include_globs/exclude_globs to narrow a call further
 
 Examples of good use cases:
 - grep: {"pattern": "func main", "path": ".", "context": 3}
 - grep: {"pattern": "TODO|FIXME", "path": "src/", "context": 1}
 - grep: {"pattern": "import.*react", "path": ".", "context": 0}
 - grep: {"pattern": "error", "path": "logs/", "context": 2}
+- grep: {"pattern": "TODO", "path": ".", "exclude_globs": ["*.min.js"]}
+- grep: {"pattern": "TODO", "paths": ["src/", "pkg/"], "shards": 4}
 `
-}
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+package grep_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/festeh/bro/internal/toolfake"
+	"github.com/festeh/bro/tools/grep"
+)
+
+// These tests drive grep through a toolfake.Sandbox, so they run the real
+// rg invocation against files the test lays out itself rather than
+// grep's in-process logic alone (see grep/shard_test.go and
+// grep/filter_test.go for those). They require rg on PATH, same as grep
+// itself does.
+func TestGrepToolSandbox(t *testing.T) {
+	sb := toolfake.New(t, map[string]string{
+		"a.go": "package main\n\nfunc main() {}\n",
+		"b.go": "package main\n\nfunc helper() {}\n",
+	})
+
+	t.Run("finds matches in the sandbox", func(t *testing.T) {
+		result := sb.Run("grep", grep.Args{Pattern: "func", Path: sb.Root})
+		if !strings.Contains(result, "a.go") || !strings.Contains(result, "b.go") {
+			t.Errorf("Expected matches from both files, got: %s", result)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		result := sb.Run("grep", grep.Args{Pattern: "nonexistent_pattern_xyz", Path: sb.Root})
+		if !strings.Contains(result, "No matches found") {
+			t.Errorf("Expected 'No matches found', got: %s", result)
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package grep
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/festeh/bro/tools"
+)
+
+const (
+	// shardDeadline bounds a single rg invocation so one bad shard can't
+	// hang the whole search.
+	shardDeadline = 20 * time.Second
+
+	// maxOutputBytes caps the merged output so a runaway pattern can't
+	// blow the model's context window.
+	maxOutputBytes = 1 << 20 // 1 MiB
+)
+
+// shard is one rg invocation: search cmdArgs's pattern/flags over paths,
+// attributed back to root for the per-root breakdown in the result message.
+type shard struct {
+	root         string
+	paths        []string
+	topLevelOnly bool // when true, pass --max-depth 1 so siblings aren't double-covered by subdir shards
+}
+
+// planShards expands roots into concrete rg invocations. With shards <= 1
+// each root runs as a single invocation. Otherwise a directory root's
+// immediate subdirectories are distributed round-robin across up to
+// `shards` buckets so independent rg processes can search them
+// concurrently, with one extra shard for the root's own top-level files.
+func planShards(roots []string, shards int) []shard {
+	if shards < 1 {
+		shards = 1
+	}
+
+	var plan []shard
+	for _, root := range roots {
+		if shards == 1 {
+			plan = append(plan, shard{root: root, paths: []string{root}})
+			continue
+		}
+
+		info, err := os.Stat(root)
+		if err != nil || !info.IsDir() {
+			plan = append(plan, shard{root: root, paths: []string{root}})
+			continue
+		}
+
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			plan = append(plan, shard{root: root, paths: []string{root}})
+			continue
+		}
+
+		var subdirs []string
+		hasFiles := false
+		for _, e := range entries {
+			if e.IsDir() {
+				subdirs = append(subdirs, filepath.Join(root, e.Name()))
+			} else {
+				hasFiles = true
+			}
+		}
+
+		if len(subdirs) == 0 {
+			plan = append(plan, shard{root: root, paths: []string{root}})
+			continue
+		}
+
+		buckets := make([][]string, shards)
+		for i, dir := range subdirs {
+			b := i % shards
+			buckets[b] = append(buckets[b], dir)
+		}
+		for _, b := range buckets {
+			if len(b) > 0 {
+				plan = append(plan, shard{root: root, paths: b})
+			}
+		}
+
+		if hasFiles {
+			plan = append(plan, shard{root: root, paths: []string{root}, topLevelOnly: true})
+		}
+	}
+
+	return plan
+}
+
+// shardOutput is what one shard's rg invocation produced.
+type shardOutput struct {
+	shard shard
+	lines []string
+	err   error
+}
+
+// runShards executes plan concurrently with a worker pool bounded by
+// runtime.NumCPU(), each invocation given flags, its root's extra glob
+// flags, the search pattern, its own paths, and a per-call deadline via
+// exec.CommandContext (parented on ctx, so canceling it - e.g. the user
+// interrupting - kills every running rg invocation). Matched lines are
+// reported to progress as each shard's rg produces them; progress.Report
+// may be called concurrently from multiple shards, so it must be safe for
+// concurrent use.
+func runShards(ctx context.Context, plan []shard, flags []string, rootFlags map[string][]string, pattern string, progress tools.ProgressReporter) []shardOutput {
+	workers := runtime.NumCPU()
+	if workers > len(plan) {
+		workers = len(plan)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int, len(plan))
+	results := make([]shardOutput, len(plan))
+
+	var lines, bytes int64
+	report := func(line string) {
+		progress.Report(tools.ProgressUpdate{
+			Line:  line,
+			Lines: int(atomic.AddInt64(&lines, 1)),
+			Bytes: int(atomic.AddInt64(&bytes, int64(len(line)))),
+		})
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runShard(ctx, plan[i], flags, rootFlags[plan[i].root], pattern, report)
+			}
+		}()
+	}
+
+	for i := range plan {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func runShard(parent context.Context, s shard, flags []string, rootFlags []string, pattern string, report func(line string)) shardOutput {
+	ctx, cancel := context.WithTimeout(parent, shardDeadline)
+	defer cancel()
+
+	cmdArgs := make([]string, 0, len(flags)+len(rootFlags)+len(s.paths)+3)
+	cmdArgs = append(cmdArgs, flags...)
+	cmdArgs = append(cmdArgs, rootFlags...)
+	if s.topLevelOnly {
+		cmdArgs = append(cmdArgs, "--max-depth", "1")
+	}
+	cmdArgs = append(cmdArgs, pattern)
+	cmdArgs = append(cmdArgs, s.paths...)
+
+	cmd := exec.CommandContext(ctx, "rg", cmdArgs...)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return shardOutput{shard: s, err: err}
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return shardOutput{shard: s, err: err}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(stdoutPipe)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines = append(lines, line)
+		report(line)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+			// No matches in this shard - not an error.
+			return shardOutput{shard: s}
+		}
+		if stderr.Len() > 0 {
+			return shardOutput{shard: s, err: &exec.ExitError{ProcessState: cmd.ProcessState, Stderr: []byte(stderr.String())}}
+		}
+		return shardOutput{shard: s, err: err}
+	}
+
+	return shardOutput{shard: s, lines: lines}
+}
+
+// mergedLine is one deduplicated, filtered match or context line tagged
+// with its root and parsed line number so the merged output can be sorted
+// by (path, line) for stable offsets.
+type mergedLine struct {
+	root    string
+	path    string
+	lineNum int
+	text    string
+}
+
+// mergeShardOutputs applies each shard's root's filter to its lines,
+// deduplicates identical lines, sorts the survivors by (path, line number),
+// and caps the result at maxOutputBytes. It returns the merged text, the
+// match count per root, the total match count, and whether the cap
+// truncated any output.
+func mergeShardOutputs(outputs []shardOutput, filters map[string]SelectFunc) (merged string, perRoot map[string]int, matchCount int, truncated bool) {
+	seen := make(map[string]bool)
+	var lines []mergedLine
+	perRoot = make(map[string]int)
+
+	for _, out := range outputs {
+		filter := filters[out.shard.root]
+		for _, line := range out.lines {
+			path, lineNum, isMatch, ok := parseRgLine(line)
+			if !ok || (filter != nil && !filter(path, nil)) {
+				continue
+			}
+			if seen[line] {
+				continue
+			}
+			seen[line] = true
+
+			lines = append(lines, mergedLine{root: out.shard.root, path: path, lineNum: lineNum, text: line})
+			if isMatch {
+				matchCount++
+				perRoot[out.shard.root]++
+			}
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].path != lines[j].path {
+			return lines[i].path < lines[j].path
+		}
+		return lines[i].lineNum < lines[j].lineNum
+	})
+
+	var b strings.Builder
+	total := 0
+	for i, l := range lines {
+		chunk := l.text
+		if i > 0 {
+			chunk = "\n" + chunk
+		}
+		if total+len(chunk) > maxOutputBytes {
+			truncated = true
+			break
+		}
+		b.WriteString(chunk)
+		total += len(chunk)
+	}
+
+	return b.String(), perRoot, matchCount, truncated
+}
+
+// parseRgLine splits an rg --line-number output line into its file path and
+// line number. rg uses ":" as the separator on both sides of the line
+// number for a match ("path:42:content") and "-" on both sides for a
+// context line ("path-42-content"), so the separator itself tells us
+// isMatch. Scanning char-by-char (rather than SplitN on a fixed separator)
+// is what makes this work for context lines at all, and also happens to
+// cope with a path that itself contains ":" or "-", since it only accepts
+// a candidate separator that's immediately followed by digits and then a
+// repeat of the same character.
+func parseRgLine(line string) (path string, lineNum int, isMatch bool, ok bool) {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c != ':' && c != '-' {
+			continue
+		}
+		j := i + 1
+		for j < len(line) && line[j] >= '0' && line[j] <= '9' {
+			j++
+		}
+		if j == i+1 || j >= len(line) || line[j] != c {
+			continue
+		}
+		lineNum, err := strconv.Atoi(line[i+1 : j])
+		if err != nil {
+			continue
+		}
+		return line[:i], lineNum, c == ':', true
+	}
+	return "", 0, false, false
+}
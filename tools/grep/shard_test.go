@@ -0,0 +1,29 @@
+package grep
+
+import "testing"
+
+func TestParseRgLineMatch(t *testing.T) {
+	path, lineNum, isMatch, ok := parseRgLine("a.go:6:\tprintln(sum)")
+	if !ok || !isMatch {
+		t.Fatalf("expected a parsed match line, got ok=%v isMatch=%v", ok, isMatch)
+	}
+	if path != "a.go" || lineNum != 6 {
+		t.Errorf("expected path=a.go line=6, got path=%q line=%d", path, lineNum)
+	}
+}
+
+func TestParseRgLineContext(t *testing.T) {
+	path, lineNum, isMatch, ok := parseRgLine("a.go-6-\tprintln(sum)")
+	if !ok || isMatch {
+		t.Fatalf("expected a parsed context line, got ok=%v isMatch=%v", ok, isMatch)
+	}
+	if path != "a.go" || lineNum != 6 {
+		t.Errorf("expected path=a.go line=6, got path=%q line=%d", path, lineNum)
+	}
+}
+
+func TestParseRgLineNoSeparator(t *testing.T) {
+	if _, _, _, ok := parseRgLine("no separator here"); ok {
+		t.Errorf("expected no match for a line with no rg separator")
+	}
+}
@@ -1,35 +1,54 @@
 package grep
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
 
+	"github.com/festeh/bro/selection"
+	"github.com/festeh/bro/tools"
 	"github.com/revrost/go-openrouter"
 )
 
 type Args struct {
-	Pattern string `json:"pattern"`
-	Path    string `json:"path,omitempty"`    // directory or file to search in, defaults to current directory
-	Context int    `json:"context,omitempty"` // number of lines of context to show around matches
+	Pattern        string   `json:"pattern"`
+	Path           string   `json:"path,omitempty"`          // directory or file to search in, defaults to current directory
+	Paths          []string `json:"paths,omitempty"`         // multiple roots to search in one call; overrides path when set
+	Shards         int      `json:"shards,omitempty"`        // fan each root's search out across up to this many concurrent rg invocations
+	Context        int      `json:"context,omitempty"`       // number of lines of context to show around matches
+	IncludeGlobs   []string `json:"include_globs,omitempty"` // per-call override: only search paths matching these globs
+	ExcludeGlobs   []string `json:"exclude_globs,omitempty"` // per-call override: skip paths matching these globs
+	IncludeIgnored bool     `json:"include_ignored,omitempty"` // search paths .broignore/.gitignore would otherwise hide; default excludes still apply
 }
 
 type Result struct {
-	Pattern  string   `json:"pattern"`
-	Path     string   `json:"path"`
-	Matches  []string `json:"matches"`
-	Count    int      `json:"count"`
-	Error    string   `json:"error,omitempty"`
+	Pattern string   `json:"pattern"`
+	Path    string   `json:"path"`
+	Matches []string `json:"matches"`
+	Count   int      `json:"count"`
+	Error   string   `json:"error,omitempty"`
 }
 
 // Tool represents the grep tool implementation
-type Tool struct{}
+type Tool struct {
+	// ctx carries the selection.Config used to build each root's SelectFunc
+	// (see selection.New), injected at construction time instead of per-call.
+	ctx tools.ToolContext
+}
 
-// NewTool creates a new grep tool instance
+// NewTool creates a new grep tool instance using the default ToolContext
+// (~/.bro/ignore.txt, or no extra excludes if that can't be read).
 func NewTool() *Tool {
-	return &Tool{}
+	return NewToolWithContext(tools.DefaultToolContext())
+}
+
+// NewToolWithContext creates a grep tool instance that builds its
+// per-root selection.SelectFunc from ctx instead of the default one.
+func NewToolWithContext(ctx tools.ToolContext) *Tool {
+	return &Tool{ctx: ctx}
 }
 
 // Name returns the tool name
@@ -42,106 +61,93 @@ func (t *Tool) Description() string {
 	return GetDescription()
 }
 
-// Execute runs the rg command with the given arguments
-func (t *Tool) Execute(args json.RawMessage) (string, error) {
+// Execute fans rg out across the requested roots/shards and merges the
+// results, reporting each matched line to progress as its shard produces
+// it. ctx bounds every shard invocation via exec.CommandContext, on top of
+// each shard's own shardDeadline.
+func (t *Tool) Execute(ctx context.Context, args json.RawMessage, progress tools.ProgressReporter) (string, error) {
 	var grepArgs Args
 	if err := json.Unmarshal(args, &grepArgs); err != nil {
 		return "", err
 	}
+	if progress == nil {
+		progress = tools.NoopProgress
+	}
 
-	// Build rg command arguments
-	cmdArgs := []string{}
-
-	// Add line numbers by default
-	cmdArgs = append(cmdArgs, "--line-number")
+	roots := grepArgs.Paths
+	if len(roots) == 0 {
+		root := grepArgs.Path
+		if root == "" {
+			root = "."
+		}
+		roots = []string{root}
+	}
 
-	// Add color for better readability
-	cmdArgs = append(cmdArgs, "--color", "never")
+	// Per-root filter and ignore-glob args, since each root may carry its
+	// own .broignore/.gitignore.
+	filters := make(map[string]SelectFunc, len(roots))
+	rootFlags := make(map[string][]string, len(roots))
+	var allIgnorePatterns []string
+	for _, root := range roots {
+		filter, patterns := selection.New(root, t.ctx.Selection, grepArgs.IncludeIgnored)
+		filters[root] = filter
+		allIgnorePatterns = append(allIgnorePatterns, patterns...)
+
+		var flags []string
+		for _, glob := range excludeGlobs(patterns) {
+			flags = append(flags, "--glob", glob)
+		}
+		rootFlags[root] = flags
+	}
 
-	// Add context if specified
+	// Flags common to every shard invocation
+	flags := []string{"--line-number", "--color", "never"}
 	if grepArgs.Context > 0 {
-		cmdArgs = append(cmdArgs, "--context", strconv.Itoa(grepArgs.Context))
+		flags = append(flags, "--context", strconv.Itoa(grepArgs.Context))
 	}
-
-	// Add the pattern
-	cmdArgs = append(cmdArgs, grepArgs.Pattern)
-
-	// Add path if specified, otherwise search current directory
-	if grepArgs.Path != "" {
-		cmdArgs = append(cmdArgs, grepArgs.Path)
-	} else {
-		cmdArgs = append(cmdArgs, ".")
+	for _, glob := range grepArgs.IncludeGlobs {
+		flags = append(flags, "--glob", glob)
+	}
+	for _, glob := range grepArgs.ExcludeGlobs {
+		flags = append(flags, "--glob", "!"+glob)
 	}
 
-	// Execute rg command
-	cmd := exec.Command("rg", cmdArgs...)
-
-	stdout, err := cmd.Output()
-
-	// Build assistant message response
-	var message strings.Builder
+	plan := planShards(roots, grepArgs.Shards)
+	outputs := runShards(ctx, plan, flags, rootFlags, grepArgs.Pattern, progress)
 
-	if err != nil {
-		// Handle errors
-		if exitError, ok := err.(*exec.ExitError); ok {
-			if exitError.ExitCode() == 1 {
-				// Exit code 1 means no matches found (normal case for rg)
-				message.WriteString(fmt.Sprintf("No matches found for pattern '%s'", grepArgs.Pattern))
-				if grepArgs.Path != "" {
-					message.WriteString(fmt.Sprintf(" in path '%s'", grepArgs.Path))
-				}
-			} else {
-				// Other exit codes indicate actual errors
-				message.WriteString(fmt.Sprintf("Error searching for pattern '%s': ", grepArgs.Pattern))
+	for _, out := range outputs {
+		if out.err != nil {
+			if exitError, ok := out.err.(*exec.ExitError); ok {
 				if stderr := string(exitError.Stderr); stderr != "" {
-					message.WriteString(stderr)
-				} else {
-					message.WriteString(fmt.Sprintf("rg exited with code %d", exitError.ExitCode()))
+					return fmt.Sprintf("Error searching for pattern '%s': %s", grepArgs.Pattern, stderr), nil
 				}
+				return fmt.Sprintf("Error searching for pattern '%s': rg exited with code %d", grepArgs.Pattern, exitError.ExitCode()), nil
 			}
-		} else {
-			message.WriteString(fmt.Sprintf("Execution error: %s", err.Error()))
+			return fmt.Sprintf("Execution error: %s", out.err.Error()), nil
 		}
-		return strings.TrimSpace(message.String()), nil
 	}
 
-	// Parse output
-	output := strings.TrimSpace(string(stdout))
+	output, perRoot, matchCount, truncated := mergeShardOutputs(outputs, filters)
 
+	var message strings.Builder
 	if output == "" {
-		// No matches found
 		message.WriteString(fmt.Sprintf("No matches found for pattern '%s'", grepArgs.Pattern))
-		if grepArgs.Path != "" {
-			message.WriteString(fmt.Sprintf(" in path '%s'", grepArgs.Path))
+		if len(roots) == 1 {
+			message.WriteString(fmt.Sprintf(" in path '%s'", roots[0]))
+		} else {
+			message.WriteString(fmt.Sprintf(" in paths %v", roots))
 		}
 	} else {
-		// Matches found
-		lines := strings.Split(output, "\n")
-		
-		// Count actual match lines (lines with line numbers, not context lines)
-		matchCount := 0
-		for _, line := range lines {
-			// Match lines have format: filename:linenumber:content
-			// Context lines have format: filename-linenumber-content
-			if strings.Contains(line, ":") && !strings.HasPrefix(line, "--") {
-				parts := strings.SplitN(line, ":", 3)
-				if len(parts) >= 2 {
-					// Check if second part is a number (line number)
-					if _, err := strconv.Atoi(parts[1]); err == nil {
-						matchCount++
-					}
-				}
-			}
-		}
-
 		if matchCount == 1 {
 			message.WriteString(fmt.Sprintf("Found 1 match for pattern '%s'", grepArgs.Pattern))
 		} else {
 			message.WriteString(fmt.Sprintf("Found %d matches for pattern '%s'", matchCount, grepArgs.Pattern))
 		}
 
-		if grepArgs.Path != "" {
-			message.WriteString(fmt.Sprintf(" in path '%s'", grepArgs.Path))
+		if len(roots) == 1 {
+			message.WriteString(fmt.Sprintf(" in path '%s'", roots[0]))
+		} else {
+			message.WriteString(fmt.Sprintf(" across %d roots", len(roots)))
 		}
 
 		if grepArgs.Context > 0 {
@@ -150,10 +156,26 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 
 		message.WriteString(":\n\n")
 		message.WriteString(output)
+
+		if len(roots) > 1 {
+			message.WriteString("\n\nPer-root breakdown:")
+			for _, root := range roots {
+				message.WriteString(fmt.Sprintf("\n  %s: %d", root, perRoot[root]))
+			}
+		}
+
+		if truncated {
+			message.WriteString(fmt.Sprintf("\n\n(output truncated at %d bytes)", maxOutputBytes))
+		}
 	}
 
-	trimmedMessage := strings.TrimSpace(message.String())
-	return trimmedMessage, nil
+	if len(allIgnorePatterns) > 0 || len(grepArgs.IncludeGlobs) > 0 || len(grepArgs.ExcludeGlobs) > 0 {
+		message.WriteString(fmt.Sprintf("\n\n(filters applied: ignore=%v include=%v exclude=%v)",
+			allIgnorePatterns, grepArgs.IncludeGlobs, grepArgs.ExcludeGlobs))
+	}
+
+	result := strings.TrimSpace(message.String())
+	return tools.TruncateResult(result, t.ctx.OutputPolicy, t.ctx.OutputCache), nil
 }
 
 // GetDefinition returns the OpenRouter tool definition
@@ -174,15 +196,39 @@ func (t *Tool) GetDefinition() openrouter.Tool {
 						"type":        "string",
 						"description": "Directory or file path to search in (defaults to current directory)",
 					},
+					"paths": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Multiple directory/file roots to search in one call; overrides path when set",
+					},
+					"shards": map[string]interface{}{
+						"type":        "integer",
+						"description": "Fan each root's search out across up to this many concurrent rg invocations (default: 1)",
+						"minimum":     1,
+					},
 					"context": map[string]interface{}{
 						"type":        "integer",
 						"description": "Number of lines of context to show around matches (default: 0)",
 						"minimum":     0,
 						"maximum":     10,
 					},
+					"include_globs": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only search paths matching these glob patterns, in addition to .broignore/.gitignore rules",
+					},
+					"exclude_globs": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Skip paths matching these glob patterns, in addition to .broignore/.gitignore rules",
+					},
+					"include_ignored": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Search paths .broignore/.gitignore would otherwise hide (default: false). Hardcoded excludes like .git and node_modules still apply.",
+					},
 				},
 				"required": []string{"pattern"},
 			},
 		},
 	}
-}
\ No newline at end of file
+}
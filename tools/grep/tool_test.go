@@ -1,9 +1,12 @@
 package grep
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/festeh/bro/tools"
 )
 
 func TestGrepTool(t *testing.T) {
@@ -22,7 +25,7 @@ func TestGrepTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -49,7 +52,7 @@ func TestGrepTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -78,7 +81,7 @@ func TestGrepTool(t *testing.T) {
 			t.Fatalf("Failed to marshal args: %v", err)
 		}
 
-		result, err := tool.Execute(argsJSON)
+		result, err := tool.Execute(context.Background(), argsJSON, tools.NoopProgress)
 		if err != nil {
 			t.Fatalf("Tool execution failed: %v", err)
 		}
@@ -1,23 +1,98 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/festeh/bro/selection"
+	"github.com/festeh/bro/tools/outputcache"
 	"github.com/revrost/go-openrouter"
 )
 
+// ToolContext carries per-session configuration that's injected into tools
+// at construction time instead of threaded through Execute args: the
+// selection.Config filesystem-traversal tools (filefinder, grep) use to
+// build their ignore/binary-aware selection.SelectFunc, and the
+// OutputPolicy/OutputCache pair bash, filefinder, grep, and readfile pass
+// to TruncateResult so an oversized result is bounded uniformly and still
+// recoverable afterward.
+type ToolContext struct {
+	Selection    *selection.Config
+	OutputPolicy OutputPolicy
+	OutputCache  *outputcache.Cache
+}
+
+// DefaultToolContext builds a ToolContext from ~/.bro/ignore.txt (falling
+// back to selection.DefaultConfig() if it can't be read), DefaultOutputPolicy,
+// and a fresh OutputCache.
+func DefaultToolContext() ToolContext {
+	cfg, err := selection.LoadConfig()
+	if err != nil {
+		cfg = selection.DefaultConfig()
+	}
+	return ToolContext{
+		Selection:    cfg,
+		OutputPolicy: DefaultOutputPolicy,
+		OutputCache:  outputcache.New(),
+	}
+}
+
+// ProgressUpdate is one incremental step of a long-running tool execution -
+// a line of output, a file found, a match hit - reported through
+// ProgressReporter so the TUI can render elapsed time and byte/line counts
+// instead of blocking silently until Execute returns.
+type ProgressUpdate struct {
+	// Line is the raw incremental output (a line of stdout/stderr, a found
+	// file path, a matched line), rendered as-is by the TUI.
+	Line string
+	// Lines and Bytes are running totals, not deltas, so the TUI can
+	// render them directly without accumulating state of its own.
+	Lines int
+	Bytes int
+}
+
+// ProgressReporter receives ProgressUpdate calls from a running tool. A Tool
+// may report from multiple goroutines concurrently (e.g. grep's sharded rg
+// invocations), so implementations must be safe for concurrent use.
+type ProgressReporter interface {
+	Report(update ProgressUpdate)
+}
+
+// NoopProgress discards every update. Tools that can't usefully report
+// progress (or callers that don't care, e.g. ExecuteTool's test helpers)
+// pass this instead of a nil ProgressReporter.
+var NoopProgress ProgressReporter = noopProgress{}
+
+type noopProgress struct{}
+
+func (noopProgress) Report(ProgressUpdate) {}
+
+// ReporterFunc adapts a plain func(ProgressUpdate) to ProgressReporter, the
+// same way http.HandlerFunc adapts a func to http.Handler - for callers
+// (like app's tool-execution loop) that just want to forward updates into
+// a channel instead of defining a named type.
+type ReporterFunc func(update ProgressUpdate)
+
+// Report calls f.
+func (f ReporterFunc) Report(update ProgressUpdate) {
+	f(update)
+}
+
 // Tool represents a tool that can be called by the LLM
 type Tool interface {
 	// Name returns the unique name of the tool
 	Name() string
-	
+
 	// Description returns a detailed description of what the tool does and when to use it
 	Description() string
-	
-	// Execute runs the tool with the given arguments and returns the result
-	Execute(args json.RawMessage) (string, error)
-	
+
+	// Execute runs the tool with the given arguments and returns the
+	// result. ctx bounds the tool's own long-running work (e.g. shelling
+	// out via exec.CommandContext) and is canceled if the user interrupts
+	// it; progress reports incremental output as it's produced.
+	Execute(ctx context.Context, args json.RawMessage, progress ProgressReporter) (string, error)
+
 	// GetDefinition returns the OpenRouter tool definition for this tool
 	GetDefinition() openrouter.Tool
 }
@@ -64,11 +139,11 @@ func (r *Registry) GetDefinitions() []openrouter.Tool {
 }
 
 // ExecuteTool executes a tool by name with the given arguments using the provided registry
-func ExecuteTool(registry *Registry, name string, args json.RawMessage) (string, error) {
+func ExecuteTool(ctx context.Context, registry *Registry, name string, args json.RawMessage, progress ProgressReporter) (string, error) {
 	tool, exists := registry.Get(name)
 	if !exists {
 		return "", fmt.Errorf("tool '%s' not found", name)
 	}
 
-	return tool.Execute(args)
-}
\ No newline at end of file
+	return tool.Execute(ctx, args, progress)
+}
@@ -0,0 +1,220 @@
+package modifyfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+// splitLines splits content into its lines, reporting separately whether
+// it ended with a trailing newline so joinLines can reconstruct it
+// exactly.
+func splitLines(content string) (lines []string, trailingNewline bool) {
+	if content == "" {
+		return nil, false
+	}
+	trailingNewline = strings.HasSuffix(content, "\n")
+	if trailingNewline {
+		content = content[:len(content)-1]
+	}
+	return strings.Split(content, "\n"), trailingNewline
+}
+
+// joinLines is the inverse of splitLines.
+func joinLines(lines []string, trailingNewline bool) string {
+	joined := strings.Join(lines, "\n")
+	if trailingNewline && len(lines) > 0 {
+		joined += "\n"
+	}
+	return joined
+}
+
+// contentLines splits an operation's new_content into lines the same way
+// splitLines does, except a trailing newline is simply dropped rather
+// than tracked - inserted/replacement text doesn't need it preserved.
+func contentLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+type diffLine struct {
+	kind byte // ' ' (context), '-' (removed), or '+' (added)
+	text string
+}
+
+// lcsDiff aligns oldLines and newLines via a longest-common-subsequence
+// line match and returns the resulting kept/removed/added lines in order.
+// The O(n*m) table is fine for the file sizes this tool edits; it isn't
+// meant for diffing huge files.
+func lcsDiff(oldLines, newLines []string) []diffLine {
+	n, m := len(oldLines), len(newLines)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case oldLines[i] == newLines[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var result []diffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			result = append(result, diffLine{' ', oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, diffLine{'-', oldLines[i]})
+			i++
+		default:
+			result = append(result, diffLine{'+', newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, diffLine{'-', oldLines[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, diffLine{'+', newLines[j]})
+	}
+	return result
+}
+
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	lines              []string
+}
+
+// findLineStart returns the old- or new-file line number a hunk should
+// report starting at index `from`, per standard unified-diff convention:
+// the number of the next surviving line of that file, or one past the
+// last surviving line if the hunk runs off the end with no such line.
+// skipKind is the diffLine kind absent from the file in question ('+' for
+// the old file's numbering, '-' for the new file's).
+func findLineStart(lines []diffLine, lineNo []int, from int, skipKind byte) int {
+	for k := from; k < len(lines); k++ {
+		if lines[k].kind != skipKind {
+			return lineNo[k]
+		}
+	}
+	for k := from - 1; k >= 0; k-- {
+		if lines[k].kind != skipKind {
+			return lineNo[k] + 1
+		}
+	}
+	return 0
+}
+
+// buildHunks groups diffLines into unified-diff hunks, each padded with up
+// to `context` lines of surrounding unchanged lines; changes close enough
+// that their context windows would overlap are merged into one hunk.
+func buildHunks(lines []diffLine, context int) []hunk {
+	oldNo := make([]int, len(lines))
+	newNo := make([]int, len(lines))
+	o, n := 1, 1
+	for idx, l := range lines {
+		switch l.kind {
+		case ' ':
+			oldNo[idx], newNo[idx] = o, n
+			o++
+			n++
+		case '-':
+			oldNo[idx] = o
+			o++
+		case '+':
+			newNo[idx] = n
+			n++
+		}
+	}
+
+	var changed []int
+	for idx, l := range lines {
+		if l.kind != ' ' {
+			changed = append(changed, idx)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	type span struct{ lo, hi int }
+	var spans []span
+	lo, hi := changed[0], changed[0]
+	for _, idx := range changed[1:] {
+		if idx-hi <= 2*context {
+			hi = idx
+			continue
+		}
+		spans = append(spans, span{lo, hi})
+		lo, hi = idx, idx
+	}
+	spans = append(spans, span{lo, hi})
+
+	var hunks []hunk
+	for _, s := range spans {
+		winLo := s.lo - context
+		if winLo < 0 {
+			winLo = 0
+		}
+		winHi := s.hi + context
+		if winHi >= len(lines) {
+			winHi = len(lines) - 1
+		}
+
+		oldCount, newCount := 0, 0
+		hlines := make([]string, 0, winHi-winLo+1)
+		for k := winLo; k <= winHi; k++ {
+			hlines = append(hlines, string(lines[k].kind)+lines[k].text)
+			if lines[k].kind != '+' {
+				oldCount++
+			}
+			if lines[k].kind != '-' {
+				newCount++
+			}
+		}
+
+		hunks = append(hunks, hunk{
+			oldStart: findLineStart(lines, oldNo, winLo, '+'),
+			oldCount: oldCount,
+			newStart: findLineStart(lines, newNo, winLo, '-'),
+			newCount: newCount,
+			lines:    hlines,
+		})
+	}
+	return hunks
+}
+
+// unifiedDiff renders a standard unified diff between oldLines and
+// newLines, or "" if they're identical.
+func unifiedDiff(path string, oldLines, newLines []string) string {
+	hunks := buildHunks(lcsDiff(oldLines, newLines), diffContext)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a%s\n", path)
+	fmt.Fprintf(&b, "+++ b%s\n", path)
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.oldStart, h.oldCount, h.newStart, h.newCount)
+		for _, line := range h.lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package modifyfile
+
+func GetDescription() string {
+	return `Edit a file by applying a batch of line-range operations in one call: replace, insert, or delete. Line numbers are 1-indexed, inclusive, and always refer to the file's original content - they don't shift as earlier operations in the same call are applied.
+
+Use this tool when you need to:
+- Make several edits to the same file in one round trip instead of one fileedit call per change
+- Edit a line range whose exact text isn't unique in the file (fileedit requires a unique old_string)
+- Insert new lines at a specific position, or delete a line range outright
+
+If you already have a change as a unified diff - e.g. one you generated yourself or received from elsewhere - use filepatch instead of converting it to operations by hand.
+
+Each operation has the form:
+- {"type": "replace", "start_line": N, "end_line": M, "new_content": "..."} - replace lines N-M with new_content
+- {"type": "insert", "start_line": N, "new_content": "..."} - insert new_content as new lines before line N (use start_line = last_line + 1 to append)
+- {"type": "delete", "start_line": N, "end_line": M} - remove lines N-M
+
+The tool will:
+- Read the file once and validate every operation's line range is in-bounds and non-overlapping before changing anything
+- Apply all operations atomically against that snapshot, then write the file once with its original permissions
+- Return a unified diff of exactly what changed
+
+Important considerations:
+- Use absolute file paths
+- Operations must not overlap; split an edit that spans a non-contiguous set of lines into separate operations
+- Line numbers always refer to the file before any operation in this call was applied
+
+Examples of good use cases:
+- modify_file: {"path": "/home/user/app.py", "operations": [{"type": "replace", "start_line": 10, "end_line": 12, "new_content": "def new_function():\n    return 42"}]}
+- modify_file: {"path": "/home/user/config.json", "operations": [{"type": "insert", "start_line": 1, "new_content": "// auto-generated"}, {"type": "delete", "start_line": 20, "end_line": 22}]}
+`
+}
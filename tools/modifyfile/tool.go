@@ -0,0 +1,262 @@
+package modifyfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
+	"github.com/revrost/go-openrouter"
+)
+
+// Operation describes one edit to apply to a file. StartLine and EndLine
+// are 1-indexed and inclusive, and always refer to the file's original
+// content - Execute applies operations back-to-front so earlier
+// operations' line numbers stay valid no matter what later ones do.
+type Operation struct {
+	Type       string `json:"type"` // "replace", "insert", or "delete"
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	NewContent string `json:"new_content,omitempty"`
+}
+
+type Args struct {
+	Path       string      `json:"path"`
+	Operations []Operation `json:"operations"`
+}
+
+type Result struct {
+	Path    string `json:"path"`
+	Diff    string `json:"diff"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Tool applies a batch of line-range edits to a file atomically against a
+// snapshot of its original content, and reports the change as a unified
+// diff.
+type Tool struct {
+	fs brofs.FS
+}
+
+// NewTool creates a new modify_file tool instance backed by the real filesystem.
+func NewTool() *Tool {
+	return &Tool{fs: brofs.NewOSFs()}
+}
+
+// NewToolWithFS creates a modify_file tool instance backed by the given
+// filesystem, e.g. brofs.NewMemFs() in tests.
+func NewToolWithFS(filesystem brofs.FS) *Tool {
+	return &Tool{fs: filesystem}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "modify_file"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return GetDescription()
+}
+
+// Execute applies editArgs.Operations to the file in one atomic write. It
+// completes fast enough not to need progress reporting or cancellation.
+func (t *Tool) Execute(_ context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
+	var editArgs Args
+	if err := json.Unmarshal(args, &editArgs); err != nil {
+		return "", err
+	}
+
+	if editArgs.Path == "" {
+		return "Error: file path is required", nil
+	}
+	if !filepath.IsAbs(editArgs.Path) {
+		return fmt.Sprintf("Error: path must be absolute, got '%s'", editArgs.Path), nil
+	}
+	if len(editArgs.Operations) == 0 {
+		return "Error: at least one operation is required", nil
+	}
+
+	fileInfo, err := t.fs.Stat(editArgs.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Sprintf("Error: file '%s' does not exist", editArgs.Path), nil
+		}
+		if os.IsPermission(err) {
+			return fmt.Sprintf("Error: permission denied accessing file '%s'", editArgs.Path), nil
+		}
+		return fmt.Sprintf("Error accessing file '%s': %s", editArgs.Path, err.Error()), nil
+	}
+	if fileInfo.IsDir() {
+		return fmt.Sprintf("Error: '%s' is a directory, not a file", editArgs.Path), nil
+	}
+
+	content, err := t.fs.ReadFile(editArgs.Path)
+	if err != nil {
+		return fmt.Sprintf("Error reading file '%s': %s", editArgs.Path, err.Error()), nil
+	}
+
+	originalLines, trailingNewline := splitLines(string(content))
+
+	ops := make([]Operation, len(editArgs.Operations))
+	copy(ops, editArgs.Operations)
+	if err := validateOperations(ops, len(originalLines)); err != nil {
+		return fmt.Sprintf("Error: %s", err.Error()), nil
+	}
+
+	// Apply back-to-front so earlier operations' line numbers, which were
+	// validated against the original content, stay valid throughout. When an
+	// insert and a replace/delete start on the same line, the replace/delete
+	// is applied first so the insert still lands before it.
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].StartLine != ops[j].StartLine {
+			return ops[i].StartLine > ops[j].StartLine
+		}
+		return ops[i].Type != "insert" && ops[j].Type == "insert"
+	})
+
+	newLines := append([]string(nil), originalLines...)
+	for _, op := range ops {
+		newLines = applyOperation(newLines, op)
+	}
+
+	newContent := joinLines(newLines, trailingNewline)
+	if err := t.fs.WriteFileAtomic(editArgs.Path, []byte(newContent), fileInfo.Mode()); err != nil {
+		return fmt.Sprintf("Error writing to file '%s': %s", editArgs.Path, err.Error()), nil
+	}
+
+	diff := unifiedDiff(editArgs.Path, originalLines, newLines)
+	if diff == "" {
+		return fmt.Sprintf("No changes applied to %s", editArgs.Path), nil
+	}
+	return fmt.Sprintf("Successfully modified %s (%d operation(s)):\n%s", editArgs.Path, len(ops), diff), nil
+}
+
+// opRange returns the 1-indexed, inclusive line range an operation
+// touches. insert touches an empty range just before StartLine, so it
+// still participates in the overlap check below without special-casing.
+func opRange(op Operation) (lo, hi int) {
+	if op.Type == "insert" {
+		return op.StartLine, op.StartLine - 1
+	}
+	return op.StartLine, op.EndLine
+}
+
+func rangesOverlap(loA, hiA, loB, hiB int) bool {
+	return loA <= hiB && loB <= hiA
+}
+
+// validateOperations checks every operation's type and bounds against
+// lineCount, then checks every pair for overlapping ranges.
+func validateOperations(ops []Operation, lineCount int) error {
+	for i, op := range ops {
+		switch op.Type {
+		case "replace", "insert", "delete":
+		default:
+			return fmt.Errorf("operation %d: unknown type %q (want replace, insert, or delete)", i, op.Type)
+		}
+
+		if op.Type == "insert" {
+			if op.StartLine < 1 || op.StartLine > lineCount+1 {
+				return fmt.Errorf("operation %d: start_line %d out of bounds (file has %d lines)", i, op.StartLine, lineCount)
+			}
+			continue
+		}
+
+		if op.StartLine < 1 || op.EndLine < op.StartLine || op.EndLine > lineCount {
+			return fmt.Errorf("operation %d: line range %d-%d out of bounds (file has %d lines)", i, op.StartLine, op.EndLine, lineCount)
+		}
+	}
+
+	for i := range ops {
+		loA, hiA := opRange(ops[i])
+		for j := i + 1; j < len(ops); j++ {
+			loB, hiB := opRange(ops[j])
+			if rangesOverlap(loA, hiA, loB, hiB) {
+				return fmt.Errorf("operations %d and %d have overlapping line ranges", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// applyOperation applies a single operation to lines. Callers must apply
+// operations in descending start_line order so that an earlier
+// operation's (still-original) line numbers never see indices shifted by
+// a later one.
+func applyOperation(lines []string, op Operation) []string {
+	switch op.Type {
+	case "delete":
+		return spliceLines(lines, op.StartLine-1, op.EndLine, nil)
+	case "replace":
+		return spliceLines(lines, op.StartLine-1, op.EndLine, contentLines(op.NewContent))
+	case "insert":
+		return spliceLines(lines, op.StartLine-1, op.StartLine-1, contentLines(op.NewContent))
+	}
+	return lines
+}
+
+// spliceLines replaces lines[from:to] (0-indexed, to exclusive) with
+// replacement. Built on a fresh slice rather than the classic
+// append(lines[:from], append(replacement, lines[to:]...)...), which
+// corrupts lines when replacement shares backing storage with it.
+func spliceLines(lines []string, from, to int, replacement []string) []string {
+	result := make([]string, 0, len(lines)-(to-from)+len(replacement))
+	result = append(result, lines[:from]...)
+	result = append(result, replacement...)
+	result = append(result, lines[to:]...)
+	return result
+}
+
+// GetDefinition returns the OpenRouter tool definition
+func (t *Tool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Absolute path to the file to modify (e.g., /home/user/file.txt)",
+					},
+					"operations": map[string]interface{}{
+						"type":        "array",
+						"description": "Line-range edits to apply together, atomically, against the file's original content",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"type": map[string]interface{}{
+									"type":        "string",
+									"enum":        []string{"replace", "insert", "delete"},
+									"description": "replace or delete a line range, or insert new_content before start_line",
+								},
+								"start_line": map[string]interface{}{
+									"type":        "integer",
+									"description": "1-indexed line number (inclusive); for insert, the line new_content is inserted before",
+								},
+								"end_line": map[string]interface{}{
+									"type":        "integer",
+									"description": "1-indexed, inclusive end line for replace/delete; ignored for insert",
+								},
+								"new_content": map[string]interface{}{
+									"type":        "string",
+									"description": "Replacement or inserted text; ignored for delete",
+								},
+							},
+							"required": []string{"type", "start_line"},
+						},
+					},
+				},
+				"required": []string{"path", "operations"},
+			},
+		},
+	}
+}
@@ -0,0 +1,309 @@
+package modifyfile
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	brofs "github.com/festeh/bro/fs"
+	"github.com/festeh/bro/tools"
+)
+
+func mustMarshal(t *testing.T, args Args) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("Failed to marshal args: %v", err)
+	}
+	return data
+}
+
+func TestModifyFileTool(t *testing.T) {
+	t.Run("replace a line range", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test1.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "replace", StartLine: 2, EndLine: 3, NewContent: "TWO\nTHREE"},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "Successfully modified") {
+			t.Errorf("Expected success message, got: %s", result)
+		}
+		if !strings.Contains(result, "-two") || !strings.Contains(result, "+TWO") {
+			t.Errorf("Expected diff to show the replacement, got: %s", result)
+		}
+
+		newContent, err := memFs.ReadFile(testFile)
+		if err != nil {
+			t.Fatalf("Failed to read modified file: %v", err)
+		}
+		if string(newContent) != "one\nTWO\nTHREE\nfour\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("insert before a line", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test2.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "insert", StartLine: 2, NewContent: "one-point-five"},
+			},
+		}
+		if _, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress); err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+
+		newContent, _ := memFs.ReadFile(testFile)
+		if string(newContent) != "one\none-point-five\ntwo\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("insert at end of file", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test3.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "insert", StartLine: 3, NewContent: "three"},
+			},
+		}
+		if _, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress); err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+
+		newContent, _ := memFs.ReadFile(testFile)
+		if string(newContent) != "one\ntwo\nthree\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("delete a line range", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test4.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "delete", StartLine: 2, EndLine: 3},
+			},
+		}
+		if _, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress); err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+
+		newContent, _ := memFs.ReadFile(testFile)
+		if string(newContent) != "one\nfour\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("multiple non-overlapping operations in one call", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test5.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\nthree\nfour\nfive\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "delete", StartLine: 1, EndLine: 1},
+				{Type: "replace", StartLine: 3, EndLine: 3, NewContent: "THREE"},
+				{Type: "insert", StartLine: 6, NewContent: "six"},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "3 operation(s)") {
+			t.Errorf("Expected operation count in result, got: %s", result)
+		}
+
+		newContent, _ := memFs.ReadFile(testFile)
+		if string(newContent) != "two\nTHREE\nfour\nfive\nsix\n" {
+			t.Errorf("Unexpected content: %q", string(newContent))
+		}
+	})
+
+	t.Run("overlapping operations are rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test6.txt"
+		originalContent := "one\ntwo\nthree\n"
+		if err := memFs.WriteFile(testFile, []byte(originalContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "replace", StartLine: 1, EndLine: 2, NewContent: "x"},
+				{Type: "delete", StartLine: 2, EndLine: 3},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "Error") || !strings.Contains(result, "overlapping") {
+			t.Errorf("Expected overlap error, got: %s", result)
+		}
+
+		content, _ := memFs.ReadFile(testFile)
+		if string(content) != originalContent {
+			t.Errorf("File should not have been modified")
+		}
+	})
+
+	t.Run("out of bounds line range is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test7.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\ntwo\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "replace", StartLine: 2, EndLine: 5, NewContent: "x"},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "Error") || !strings.Contains(result, "out of bounds") {
+			t.Errorf("Expected out-of-bounds error, got: %s", result)
+		}
+	})
+
+	t.Run("unknown operation type is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+		testFile := "/fake/test8.txt"
+		if err := memFs.WriteFile(testFile, []byte("one\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		args := Args{
+			Path: testFile,
+			Operations: []Operation{
+				{Type: "append", StartLine: 1, EndLine: 1, NewContent: "x"},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "unknown type") {
+			t.Errorf("Expected unknown-type error, got: %s", result)
+		}
+	})
+
+	t.Run("non-existent file", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+
+		args := Args{
+			Path: "/fake/nonexistent.txt",
+			Operations: []Operation{
+				{Type: "replace", StartLine: 1, EndLine: 1, NewContent: "x"},
+			},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "does not exist") {
+			t.Errorf("Expected 'does not exist' in result, got: %s", result)
+		}
+	})
+
+	t.Run("relative path is rejected", func(t *testing.T) {
+		memFs := brofs.NewMemFs()
+		tool := NewToolWithFS(memFs)
+
+		args := Args{
+			Path:       "relative/path.txt",
+			Operations: []Operation{{Type: "delete", StartLine: 1, EndLine: 1}},
+		}
+		result, err := tool.Execute(context.Background(), mustMarshal(t, args), tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Tool execution failed: %v", err)
+		}
+		if !strings.Contains(result, "must be absolute") {
+			t.Errorf("Expected 'must be absolute' in result, got: %s", result)
+		}
+	})
+}
+
+func TestModifyFileToolDefinition(t *testing.T) {
+	tool := NewTool()
+
+	if tool.Name() != "modify_file" {
+		t.Errorf("Expected tool name 'modify_file', got '%s'", tool.Name())
+	}
+
+	if tool.Description() == "" {
+		t.Error("Tool description should not be empty")
+	}
+
+	def := tool.GetDefinition()
+	if def.Function.Name != "modify_file" {
+		t.Errorf("Expected function name 'modify_file', got '%s'", def.Function.Name)
+	}
+
+	params := def.Function.Parameters.(map[string]interface{})
+	props := params["properties"].(map[string]interface{})
+	for _, field := range []string{"path", "operations"} {
+		if _, exists := props[field]; !exists {
+			t.Errorf("Expected '%s' parameter to exist", field)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	diff := unifiedDiff("/fake/test.txt", []string{"a", "b", "c"}, []string{"a", "B", "c"})
+	if !strings.Contains(diff, "--- a/fake/test.txt") {
+		t.Errorf("Expected old-file header, got: %s", diff)
+	}
+	if !strings.Contains(diff, "-b") || !strings.Contains(diff, "+B") {
+		t.Errorf("Expected line-level change markers, got: %s", diff)
+	}
+
+	if unifiedDiff("/fake/test.txt", []string{"a"}, []string{"a"}) != "" {
+		t.Error("Expected empty diff for identical content")
+	}
+}
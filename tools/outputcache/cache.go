@@ -0,0 +1,73 @@
+// Package outputcache holds the full, untruncated text of a tool result
+// after tools.TruncateResult has trimmed what actually goes back into the
+// transcript. Each entry is keyed by a short id that the truncation notice
+// tells the model about, so a follow-up readfile call with that id can pull
+// a further slice of the real output instead of the model re-running a
+// possibly expensive tool call just to see more of what it already fetched.
+package outputcache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// Cache maps generated ids to the full text Put stored for them. It's safe
+// for concurrent use, since tool calls across a session's lifetime (and
+// within it, grep's sharded ones) may store and read entries at once.
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+// New creates an empty Cache.
+func New() *Cache {
+	return &Cache{entries: make(map[string]string)}
+}
+
+// Put stores content under a freshly generated id and returns it.
+func (c *Cache) Put(content string) (string, error) {
+	id, err := randomID()
+	if err != nil {
+		return "", fmt.Errorf("generating output cache id: %w", err)
+	}
+
+	c.mu.Lock()
+	c.entries[id] = content
+	c.mu.Unlock()
+
+	return id, nil
+}
+
+// Slice returns content[offset:offset+length] for id, clamped to what's
+// actually stored (a negative length means "to the end"). ok is false if id
+// isn't known.
+func (c *Cache) Slice(id string, offset, length int) (content string, ok bool) {
+	c.mu.RLock()
+	full, exists := c.entries[id]
+	c.mu.RUnlock()
+	if !exists {
+		return "", false
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(full) {
+		offset = len(full)
+	}
+	end := len(full)
+	if length >= 0 && offset+length < end {
+		end = offset + length
+	}
+	return full[offset:end], true
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/festeh/bro/tools/outputcache"
+)
+
+// Truncation strategies TruncateResult understands, configured through
+// config.yaml's "output:" section (see config.Config.Output).
+const (
+	StrategyHead      = "head"
+	StrategyTail      = "tail"
+	StrategyHeadTail  = "head+tail"
+	StrategySummarize = "summarize"
+)
+
+// OutputPolicy bounds how much of a tool's result makes it back into the
+// transcript, applied uniformly by TruncateResult. A zero OutputPolicy
+// means "use DefaultOutputPolicy" for every field it leaves unset - the
+// same "0 means inherit" convention config.ModelEntry uses for
+// Temperature/MaxTokens.
+type OutputPolicy struct {
+	MaxBytes int
+	MaxLines int
+	Strategy string
+}
+
+// DefaultOutputPolicy is what TruncateResult falls back to for any field an
+// OutputPolicy leaves at zero.
+var DefaultOutputPolicy = OutputPolicy{
+	MaxBytes: 64 * 1024,
+	MaxLines: 500,
+	Strategy: StrategyHeadTail,
+}
+
+// TruncateResult trims result down to policy's bounds when it exceeds
+// them, storing the untruncated text in cache and appending a notice
+// telling the model how much was cut and what id to pass to readfile's
+// output_id argument to inspect the rest. bash, filefinder, grep, and
+// readfile - the tools whose output can blow up the context window - call
+// this on their assistant message just before returning it. cache may be
+// nil, in which case the notice is still appended but the full text isn't
+// recoverable afterward.
+func TruncateResult(result string, policy OutputPolicy, cache *outputcache.Cache) string {
+	if policy.MaxBytes <= 0 {
+		policy.MaxBytes = DefaultOutputPolicy.MaxBytes
+	}
+	if policy.MaxLines <= 0 {
+		policy.MaxLines = DefaultOutputPolicy.MaxLines
+	}
+	if policy.Strategy == "" {
+		policy.Strategy = DefaultOutputPolicy.Strategy
+	}
+
+	lines := strings.Split(result, "\n")
+	if len(result) <= policy.MaxBytes && len(lines) <= policy.MaxLines {
+		return result
+	}
+
+	picked, omitted := truncateLines(lines, policy)
+	truncated := strings.Join(picked, "\n")
+	if len(truncated) > policy.MaxBytes {
+		truncated = truncated[:policy.MaxBytes]
+	}
+
+	notice := fmt.Sprintf("\n\n… %d lines omitted, %s total …", omitted, humanBytes(len(result)))
+	if cache != nil {
+		if id, err := cache.Put(result); err == nil {
+			notice = fmt.Sprintf("\n\n… %d lines omitted, %s total, use `readfile` with output_id=%q and an offset to inspect the rest …",
+				omitted, humanBytes(len(result)), id)
+		}
+	}
+
+	return truncated + notice
+}
+
+// truncateLines applies policy.Strategy to lines, returning what survives
+// and how many lines were cut. "summarize" has no model to summarize with
+// here, so it falls back to a structural summary (first/last line plus a
+// count) instead of the content window the other strategies keep.
+func truncateLines(lines []string, policy OutputPolicy) (picked []string, omitted int) {
+	max := policy.MaxLines
+	if max <= 0 || max >= len(lines) {
+		return lines, 0
+	}
+
+	switch policy.Strategy {
+	case StrategyHead:
+		return lines[:max], len(lines) - max
+	case StrategyTail:
+		return lines[len(lines)-max:], len(lines) - max
+	case StrategySummarize:
+		return []string{
+			lines[0],
+			fmt.Sprintf("... (%d lines omitted) ...", len(lines)-2),
+			lines[len(lines)-1],
+		}, len(lines) - 2
+	default: // head+tail
+		head := max / 2
+		tail := max - head
+		merged := make([]string, 0, head+tail)
+		merged = append(merged, lines[:head]...)
+		merged = append(merged, lines[len(lines)-tail:]...)
+		return merged, len(lines) - head - tail
+	}
+}
+
+// humanBytes renders n as e.g. "842 B", "1.2 KiB", "3.4 MiB".
+func humanBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for n/int(div) >= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -2,17 +2,29 @@ package readfile
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/festeh/bro/tools"
 	"github.com/revrost/go-openrouter"
 )
 
 type Args struct {
 	Path string `json:"path"`
+	// OutputID, when set, reads a slice out of a previous tool call's cached
+	// full output (see tools.TruncateResult's notice) instead of Path - the
+	// follow-up half of that truncation notice's promise.
+	OutputID string `json:"output_id,omitempty"`
+	// Offset is the starting byte offset into the cached output named by
+	// OutputID. Ignored unless OutputID is set.
+	Offset int `json:"offset,omitempty"`
+	// Length caps how many bytes of the cached output to return starting at
+	// Offset; 0 means "to the end". Ignored unless OutputID is set.
+	Length int `json:"length,omitempty"`
 }
 
 type Result struct {
@@ -27,11 +39,24 @@ type Result struct {
 const MAX_LINES = 200
 
 // Tool represents the readfile tool implementation
-type Tool struct{}
+type Tool struct {
+	// ctx carries the OutputCache that OutputID reads pull from, and the
+	// OutputPolicy applied as a safety net on top of MAX_LINES, injected at
+	// construction time.
+	ctx tools.ToolContext
+}
 
-// NewTool creates a new readfile tool instance
+// NewTool creates a new readfile tool instance using the default
+// ToolContext (DefaultOutputPolicy, a fresh OutputCache).
 func NewTool() *Tool {
-	return &Tool{}
+	return NewToolWithContext(tools.DefaultToolContext())
+}
+
+// NewToolWithContext creates a readfile tool instance that reads OutputID
+// lookups from ctx's OutputCache and bounds its output with ctx's
+// OutputPolicy instead of the default ones.
+func NewToolWithContext(ctx tools.ToolContext) *Tool {
+	return &Tool{ctx: ctx}
 }
 
 // Name returns the tool name
@@ -44,13 +69,18 @@ func (t *Tool) Description() string {
 	return GetDescription()
 }
 
-// Execute reads a file with the given path
-func (t *Tool) Execute(args json.RawMessage) (string, error) {
+// Execute reads a file with the given path. It completes fast enough not
+// to need progress reporting or cancellation.
+func (t *Tool) Execute(_ context.Context, args json.RawMessage, _ tools.ProgressReporter) (string, error) {
 	var readArgs Args
 	if err := json.Unmarshal(args, &readArgs); err != nil {
 		return "", err
 	}
 
+	if readArgs.OutputID != "" {
+		return t.readCached(readArgs)
+	}
+
 	// Validate that path is provided
 	if readArgs.Path == "" {
 		return "Error: file path is required", nil
@@ -130,7 +160,26 @@ func (t *Tool) Execute(args json.RawMessage) (string, error) {
 		message.WriteString(fmt.Sprintf("File continues for %d more lines...", lineCount-MAX_LINES))
 	}
 
-	return strings.TrimSpace(message.String()), nil
+	return tools.TruncateResult(strings.TrimSpace(message.String()), t.ctx.OutputPolicy, t.ctx.OutputCache), nil
+}
+
+// readCached serves a slice of a previous tool call's full, untruncated
+// output out of t.ctx.OutputCache instead of reading a file from disk.
+func (t *Tool) readCached(args Args) (string, error) {
+	if t.ctx.OutputCache == nil {
+		return fmt.Sprintf("Error: no cached output available for output_id '%s'", args.OutputID), nil
+	}
+
+	length := args.Length
+	if length <= 0 {
+		length = -1
+	}
+	slice, ok := t.ctx.OutputCache.Slice(args.OutputID, args.Offset, length)
+	if !ok {
+		return fmt.Sprintf("Error: unknown output_id '%s'", args.OutputID), nil
+	}
+
+	return fmt.Sprintf("Cached output %s, offset %d:\n\n%s", args.OutputID, args.Offset, slice), nil
 }
 
 // GetDefinition returns the OpenRouter tool definition
@@ -147,6 +196,20 @@ func (t *Tool) GetDefinition() openrouter.Tool {
 						"type":        "string",
 						"description": "Absolute path to the file to read (e.g., /home/user/file.txt)",
 					},
+					"output_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Instead of reading path, fetch a slice of a previous tool call's full output using the output_id from its truncation notice",
+					},
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Byte offset into the cached output to start from (only used with output_id)",
+						"minimum":     0,
+					},
+					"length": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of bytes of cached output to return starting at offset (only used with output_id, default: to the end)",
+						"minimum":     1,
+					},
 				},
 				"required": []string{"path"},
 			},
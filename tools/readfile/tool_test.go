@@ -1,47 +1,23 @@
-package readfile
+package readfile_test
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/festeh/bro/internal/toolfake"
+	"github.com/festeh/bro/tools/readfile"
 )
 
 func TestReadFileTool(t *testing.T) {
-	tool := NewTool()
-
-	// Create temp directory for test files
-	tempDir, err := os.MkdirTemp("", "readfile_test")
-	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
-
 	// Test reading a small file
 	t.Run("read small file", func(t *testing.T) {
-		// Create a small test file
-		testFile := filepath.Join(tempDir, "small.txt")
-		content := "line 1\nline 2\nline 3\n"
-		err := os.WriteFile(testFile, []byte(content), 0644)
-		if err != nil {
-			t.Fatalf("Failed to create test file: %v", err)
-		}
-
-		args := Args{Path: testFile}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
-
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
-		}
+		sb := toolfake.New(t, map[string]string{
+			"small.txt": "line 1\nline 2\nline 3\n",
+		})
+		testFile := sb.Path("small.txt")
 
-		message := result
-		t.Logf("Small file result: %s", message)
+		message := sb.Run("readfile", readfile.Args{Path: testFile})
 
 		// Should contain file path, line numbers, and content
 		if !strings.Contains(message, testFile) {
@@ -60,30 +36,14 @@ func TestReadFileTool(t *testing.T) {
 
 	// Test reading a large file (> 200 lines)
 	t.Run("read large file with truncation", func(t *testing.T) {
-		// Create a large test file
-		testFile := filepath.Join(tempDir, "large.txt")
 		var content strings.Builder
 		for i := 1; i <= 250; i++ {
 			content.WriteString(fmt.Sprintf("This is line %d\n", i))
 		}
-		err := os.WriteFile(testFile, []byte(content.String()), 0644)
-		if err != nil {
-			t.Fatalf("Failed to create test file: %v", err)
-		}
-
-		args := Args{Path: testFile}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
+		sb := toolfake.New(t, map[string]string{"large.txt": content.String()})
+		testFile := sb.Path("large.txt")
 
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
-		}
-
-		message := result
-		t.Logf("Large file result length: %d", len(message))
+		message := sb.Run("readfile", readfile.Args{Path: testFile})
 
 		// Should contain truncation message
 		if !strings.Contains(message, "truncated") {
@@ -106,25 +66,10 @@ func TestReadFileTool(t *testing.T) {
 
 	// Test reading empty file
 	t.Run("read empty file", func(t *testing.T) {
-		testFile := filepath.Join(tempDir, "empty.txt")
-		err := os.WriteFile(testFile, []byte(""), 0644)
-		if err != nil {
-			t.Fatalf("Failed to create test file: %v", err)
-		}
+		sb := toolfake.New(t, map[string]string{"empty.txt": ""})
+		testFile := sb.Path("empty.txt")
 
-		args := Args{Path: testFile}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
-
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
-		}
-
-		message := result
-		t.Logf("Empty file result: %s", message)
+		message := sb.Run("readfile", readfile.Args{Path: testFile})
 
 		if !strings.Contains(message, "0 lines") {
 			t.Errorf("Expected 0 lines for empty file, got: %s", message)
@@ -136,79 +81,46 @@ func TestReadFileTool(t *testing.T) {
 
 	// Test non-existent file
 	t.Run("read non-existent file", func(t *testing.T) {
-		args := Args{Path: "/nonexistent/file.txt"}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
+		sb := toolfake.New(t, nil)
+		result := sb.Run("readfile", readfile.Args{Path: "/nonexistent/file.txt"})
 
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
+		if !strings.Contains(result, "Error") {
+			t.Errorf("Expected error message for non-existent file, got: %s", result)
 		}
-
-		message := result
-		t.Logf("Non-existent file result: %s", message)
-
-		if !strings.Contains(message, "Error") {
-			t.Errorf("Expected error message for non-existent file, got: %s", message)
-		}
-		if !strings.Contains(message, "does not exist") {
-			t.Errorf("Expected 'does not exist' in error message, got: %s", message)
+		if !strings.Contains(result, "does not exist") {
+			t.Errorf("Expected 'does not exist' in error message, got: %s", result)
 		}
 	})
 
 	// Test relative path (should fail)
 	t.Run("read with relative path", func(t *testing.T) {
-		args := Args{Path: "relative/path.txt"}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
+		sb := toolfake.New(t, nil)
+		result := sb.Run("readfile", readfile.Args{Path: "relative/path.txt"})
 
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
+		if !strings.Contains(result, "Error") {
+			t.Errorf("Expected error message for relative path, got: %s", result)
 		}
-
-		message := result
-		t.Logf("Relative path result: %s", message)
-
-		if !strings.Contains(message, "Error") {
-			t.Errorf("Expected error message for relative path, got: %s", message)
-		}
-		if !strings.Contains(message, "must be absolute") {
-			t.Errorf("Expected 'must be absolute' in error message, got: %s", message)
+		if !strings.Contains(result, "must be absolute") {
+			t.Errorf("Expected 'must be absolute' in error message, got: %s", result)
 		}
 	})
 
 	// Test directory instead of file
 	t.Run("read directory", func(t *testing.T) {
-		args := Args{Path: tempDir}
-		argsJSON, err := json.Marshal(args)
-		if err != nil {
-			t.Fatalf("Failed to marshal args: %v", err)
-		}
-
-		result, err := tool.Execute(argsJSON)
-		if err != nil {
-			t.Fatalf("Tool execution failed: %v", err)
-		}
-
-		message := result
-		t.Logf("Directory result: %s", message)
+		sb := toolfake.New(t, map[string]string{"placeholder.txt": ""})
+		result := sb.Run("readfile", readfile.Args{Path: sb.Root})
 
-		if !strings.Contains(message, "Error") {
-			t.Errorf("Expected error message for directory, got: %s", message)
+		if !strings.Contains(result, "Error") {
+			t.Errorf("Expected error message for directory, got: %s", result)
 		}
-		if !strings.Contains(message, "is a directory") {
-			t.Errorf("Expected 'is a directory' in error message, got: %s", message)
+		if !strings.Contains(result, "is a directory") {
+			t.Errorf("Expected 'is a directory' in error message, got: %s", result)
 		}
 	})
 }
 
 func TestReadFileToolDefinition(t *testing.T) {
-	tool := NewTool()
+	tool := readfile.NewTool()
 
 	// Test tool metadata
 	if tool.Name() != "readfile" {
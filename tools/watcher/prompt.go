@@ -0,0 +1,30 @@
+package watcher
+
+func GetDescription() string {
+	return `Block until a file changes under one or more directories, or until a timeout elapses. Useful for reacting to changes you can't predict the timing of - waiting for a test runner to regenerate output, or for a build watcher to rewrite a bundle - instead of polling with repeated readfile calls.
+
+Use this tool when you need to:
+- Wait for a file you just triggered a rebuild/test/codegen for to be rewritten
+- Re-read files after an edit you expect some other process to react to
+- Detect whether anything changed in a directory within a bounded window
+
+The tool will return a JSON object with:
+- changes: a list of {path, op} pairs, op being one of create/write/rename/remove
+- timed_out: true if the timeout elapsed with no matching change at all
+
+Behavior:
+- Watches paths recursively; .broignore/.gitignore/default excludes (.git, node_modules, etc.) are skipped like every other filesystem-traversal tool
+- Events arriving within ~200ms of the first matching one are coalesced into one batch, so a single save that fires several write events is reported once
+- extensions filters which files are worth waking up for (e.g. only ".go" changes); omit it to match any file
+
+Important considerations:
+- paths must already exist; a path that disappears mid-watch is not an error, its removal is just reported like any other change
+- timeout_ms bounds how long the call blocks - keep it well under your own turn budget (default 30000ms)
+- This tool does not return file contents; follow up with readfile once a change is reported
+
+Examples of good use cases:
+- watcher: {"paths": ["/home/user/project/dist"], "timeout_ms": 15000}
+- watcher: {"paths": ["/home/user/project/src"], "extensions": [".go"], "timeout_ms": 10000}
+- watcher: {"paths": ["/home/user/project"], "ignore": ["*.log"], "timeout_ms": 5000}
+`
+}
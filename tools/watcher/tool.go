@@ -0,0 +1,245 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/festeh/bro/selection"
+	"github.com/festeh/bro/tools"
+	"github.com/fsnotify/fsnotify"
+	"github.com/revrost/go-openrouter"
+)
+
+const (
+	// defaultTimeout is how long Execute waits for a matching event before
+	// giving up and reporting no changes.
+	defaultTimeout = 30 * time.Second
+	// debounceWindow is how long Execute keeps coalescing events into the
+	// same batch after the first matching one arrives, so a save that
+	// fires several Write events (editors routinely do) is reported once.
+	debounceWindow = 200 * time.Millisecond
+)
+
+type Args struct {
+	Paths      []string `json:"paths"`
+	Extensions []string `json:"extensions,omitempty"` // only report changes to files with one of these extensions (e.g. ".go"); empty means all files
+	Ignore     []string `json:"ignore,omitempty"`     // extra .broignore-style patterns for this call, on top of the configured ToolContext excludes
+	TimeoutMs  int      `json:"timeout_ms,omitempty"` // how long to wait for a change before giving up; defaults to 30000
+}
+
+// Change describes one coalesced filesystem change.
+type Change struct {
+	Path string `json:"path"`
+	Op   string `json:"op"` // "create", "write", "rename", or "remove"
+}
+
+type Result struct {
+	Changes  []Change `json:"changes"`
+	TimedOut bool     `json:"timed_out"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Tool represents the watcher tool implementation
+type Tool struct {
+	// ctx carries the selection.Config used to build each watched root's
+	// SelectFunc (see selection.New), injected at construction time
+	// instead of per-call, matching grep/filefinder.
+	ctx tools.ToolContext
+}
+
+// NewTool creates a new watcher tool instance using the default
+// ToolContext (~/.bro/ignore.txt, or no extra excludes if that can't be read).
+func NewTool() *Tool {
+	return NewToolWithContext(tools.DefaultToolContext())
+}
+
+// NewToolWithContext creates a watcher tool instance that builds its
+// per-root selection.SelectFunc from ctx instead of the default one.
+func NewToolWithContext(ctx tools.ToolContext) *Tool {
+	return &Tool{ctx: ctx}
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "watcher"
+}
+
+// Description returns the tool description
+func (t *Tool) Description() string {
+	return GetDescription()
+}
+
+// Execute blocks until a filesystem change matching args' filters occurs
+// under one of args.Paths, or until the timeout elapses, then returns a
+// JSON list of the changed paths and their event types. Each matching
+// change is also reported to progress as it's seen, and ctx being canceled
+// (e.g. the user interrupting) stops the wait early, same as a timeout.
+func (t *Tool) Execute(ctx context.Context, args json.RawMessage, progress tools.ProgressReporter) (string, error) {
+	var watchArgs Args
+	if err := json.Unmarshal(args, &watchArgs); err != nil {
+		return "", err
+	}
+	if progress == nil {
+		progress = tools.NoopProgress
+	}
+
+	if len(watchArgs.Paths) == 0 {
+		return "Error: at least one path is required in 'paths'", nil
+	}
+
+	timeout := defaultTimeout
+	if watchArgs.TimeoutMs > 0 {
+		timeout = time.Duration(watchArgs.TimeoutMs) * time.Millisecond
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return "Error: failed to start filesystem watcher: " + err.Error(), nil
+	}
+	defer w.Close()
+
+	cfg := &selection.Config{ExtraExcludes: append(append([]string{}, t.ctx.Selection.ExtraExcludes...), watchArgs.Ignore...)}
+	for _, root := range watchArgs.Paths {
+		filter, _ := selection.New(root, cfg, false)
+		if err := addRecursive(w, root, filter); err != nil {
+			return "Error: failed to watch path '" + root + "': " + err.Error(), nil
+		}
+	}
+
+	changes, timedOut := collect(ctx, w, watchArgs.Extensions, timeout, progress)
+
+	result := Result{Changes: changes, TimedOut: timedOut}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// collect reads events off w until one matching extensions arrives, then
+// keeps coalescing further events for debounceWindow before returning, or
+// returns with TimedOut set if timeout elapses with no matching event at
+// all. It also returns early (TimedOut set, same as a timeout) if ctx is
+// canceled.
+func collect(ctx context.Context, w *fsnotify.Watcher, extensions []string, timeout time.Duration, progress tools.ProgressReporter) ([]Change, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	var debounce *time.Timer
+	seen := make(map[string]string)
+	var order []string
+
+	for {
+		var debounceC <-chan time.Time
+		if debounce != nil {
+			debounceC = debounce.C
+		}
+
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return renderChanges(seen, order), false
+			}
+			op := opName(event.Op)
+			if op == "" || !matchesExtensions(event.Name, extensions) {
+				continue
+			}
+			if _, exists := seen[event.Name]; !exists {
+				order = append(order, event.Name)
+			}
+			seen[event.Name] = op
+			progress.Report(tools.ProgressUpdate{Line: op + " " + event.Name, Lines: len(order)})
+			if debounce == nil {
+				debounce = time.NewTimer(debounceWindow)
+				defer debounce.Stop()
+			}
+		case <-w.Errors:
+			// A watch error on one path shouldn't abort the whole call;
+			// keep waiting for events from the rest.
+			continue
+		case <-debounceC:
+			return renderChanges(seen, order), false
+		case <-deadline.C:
+			return renderChanges(seen, order), len(seen) == 0
+		case <-ctx.Done():
+			return renderChanges(seen, order), len(seen) == 0
+		}
+	}
+}
+
+func renderChanges(seen map[string]string, order []string) []Change {
+	changes := make([]Change, 0, len(order))
+	for _, path := range order {
+		changes = append(changes, Change{Path: path, Op: seen[path]})
+	}
+	return changes
+}
+
+// opName maps an fsnotify.Op (a bitmask, since a single event can in theory
+// carry more than one bit) to the single most relevant label, in the
+// priority order the request calls out: Create, Write, Rename, Remove.
+func opName(op fsnotify.Op) string {
+	switch {
+	case op&fsnotify.Create != 0:
+		return "create"
+	case op&fsnotify.Write != 0:
+		return "write"
+	case op&fsnotify.Rename != 0:
+		return "rename"
+	case op&fsnotify.Remove != 0:
+		return "remove"
+	default:
+		return ""
+	}
+}
+
+func matchesExtensions(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(path)
+	for _, want := range extensions {
+		if ext == want {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDefinition returns the OpenRouter tool definition
+func (t *Tool) GetDefinition() openrouter.Tool {
+	return openrouter.Tool{
+		Type: openrouter.ToolTypeFunction,
+		Function: &openrouter.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"paths": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Directories to watch recursively for changes",
+					},
+					"extensions": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only report changes to files with one of these extensions (e.g. [\".go\"]). Omit to report every file.",
+					},
+					"ignore": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": ".broignore-style patterns to exclude for this call, on top of .broignore/.gitignore/defaults",
+					},
+					"timeout_ms": map[string]interface{}{
+						"type":        "integer",
+						"description": "How long to wait for a matching change before giving up, in milliseconds (default 30000)",
+					},
+				},
+				"required": []string{"paths"},
+			},
+		},
+	}
+}
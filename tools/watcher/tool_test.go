@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/festeh/bro/tools"
+)
+
+func TestWatcherTool(t *testing.T) {
+	tool := NewTool()
+
+	tempDir, err := os.MkdirTemp("", "watcher_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	t.Run("reports a write within the timeout", func(t *testing.T) {
+		target := filepath.Join(tempDir, "watched.txt")
+		if err := os.WriteFile(target, []byte("initial"), 0644); err != nil {
+			t.Fatalf("Failed to seed file: %v", err)
+		}
+
+		done := make(chan string, 1)
+		go func() {
+			args, _ := json.Marshal(Args{Paths: []string{tempDir}, TimeoutMs: 5000})
+			result, execErr := tool.Execute(context.Background(), args, tools.NoopProgress)
+			if execErr != nil {
+				t.Errorf("Execute returned error: %v", execErr)
+			}
+			done <- result
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		if err := os.WriteFile(target, []byte("changed"), 0644); err != nil {
+			t.Fatalf("Failed to rewrite file: %v", err)
+		}
+
+		select {
+		case result := <-done:
+			var parsed Result
+			if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+				t.Fatalf("Failed to parse result JSON: %v\nresult: %s", err, result)
+			}
+			if parsed.TimedOut {
+				t.Errorf("Expected a matching change, got timed_out=true: %s", result)
+			}
+			found := false
+			for _, c := range parsed.Changes {
+				if c.Path == target {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected %s in changes, got: %s", target, result)
+			}
+		case <-time.After(6 * time.Second):
+			t.Fatal("Execute did not return in time")
+		}
+	})
+
+	t.Run("times out when nothing changes", func(t *testing.T) {
+		args, _ := json.Marshal(Args{Paths: []string{tempDir}, TimeoutMs: 300})
+		result, err := tool.Execute(context.Background(), args, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		var parsed Result
+		if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+			t.Fatalf("Failed to parse result JSON: %v\nresult: %s", err, result)
+		}
+		if !parsed.TimedOut {
+			t.Errorf("Expected timed_out=true, got: %s", result)
+		}
+		if len(parsed.Changes) != 0 {
+			t.Errorf("Expected no changes, got: %v", parsed.Changes)
+		}
+	})
+
+	t.Run("extensions filter non-matching changes", func(t *testing.T) {
+		ignored := filepath.Join(tempDir, "notes.md")
+
+		done := make(chan string, 1)
+		go func() {
+			args, _ := json.Marshal(Args{Paths: []string{tempDir}, Extensions: []string{".go"}, TimeoutMs: 500})
+			result, execErr := tool.Execute(context.Background(), args, tools.NoopProgress)
+			if execErr != nil {
+				t.Errorf("Execute returned error: %v", execErr)
+			}
+			done <- result
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		if err := os.WriteFile(ignored, []byte("hello"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		select {
+		case result := <-done:
+			var parsed Result
+			if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+				t.Fatalf("Failed to parse result JSON: %v\nresult: %s", err, result)
+			}
+			if !parsed.TimedOut {
+				t.Errorf("Expected a .md change to be filtered out, got: %s", result)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Execute did not return in time")
+		}
+	})
+
+	t.Run("missing paths is an error", func(t *testing.T) {
+		args, _ := json.Marshal(Args{})
+		result, err := tool.Execute(context.Background(), args, tools.NoopProgress)
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		if result != "Error: at least one path is required in 'paths'" {
+			t.Errorf("Expected missing-paths error, got: %s", result)
+		}
+	})
+}
+
+func TestWatcherToolDefinition(t *testing.T) {
+	tool := NewTool()
+
+	if tool.Name() != "watcher" {
+		t.Errorf("Expected tool name 'watcher', got '%s'", tool.Name())
+	}
+	if tool.Description() == "" {
+		t.Error("Tool description should not be empty")
+	}
+
+	def := tool.GetDefinition()
+	if def.Function.Name != "watcher" {
+		t.Errorf("Expected function name 'watcher', got '%s'", def.Function.Name)
+	}
+
+	params := def.Function.Parameters.(map[string]interface{})
+	props := params["properties"].(map[string]interface{})
+	if _, exists := props["paths"]; !exists {
+		t.Error("Expected 'paths' parameter to exist")
+	}
+
+	required := params["required"].([]string)
+	if len(required) != 1 || required[0] != "paths" {
+		t.Errorf("Expected required parameters to be ['paths'], got %v", required)
+	}
+}
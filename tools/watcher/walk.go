@@ -0,0 +1,36 @@
+package watcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/festeh/bro/selection"
+	"github.com/fsnotify/fsnotify"
+)
+
+// addRecursive walks root and adds every directory that filter accepts to w,
+// so fsnotify (which only watches one directory at a time) reports events
+// for the whole subtree. Directories filter rejects are skipped entirely -
+// their contents are never walked or watched.
+func addRecursive(w *fsnotify.Watcher, root string, filter selection.SelectFunc) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		info, statErr := d.Info()
+		if statErr == nil && !filter(path, info) {
+			return filepath.SkipDir
+		}
+		if addErr := w.Add(path); addErr != nil {
+			if os.IsNotExist(addErr) || os.IsPermission(addErr) {
+				return filepath.SkipDir
+			}
+			return addErr
+		}
+		return nil
+	})
+}